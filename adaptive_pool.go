@@ -0,0 +1,256 @@
+package merec
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// LimitSample is the summary of the calls finished during one interval of the adaptive worker pool.
+type LimitSample struct {
+	// Calls is the number of the finished calls.
+	Calls int
+	// Failures is the number of the failed calls.
+	Failures int
+	// AvgLatency is the average duration of the calls.
+	AvgLatency time.Duration
+	// MinLatency is the shortest duration of the calls.
+	MinLatency time.Duration
+}
+
+// FailureRate returns the share of the failed calls.
+func (s LimitSample) FailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+
+	return float64(s.Failures) / float64(s.Calls)
+}
+
+// ConcurrencyLimit picks the number of workers of the adaptive worker pool.
+type ConcurrencyLimit interface {
+	// Next returns the new number of workers for the current one and the sample of the calls executed with it.
+	// The result is clamped to the configured bounds by the pool.
+	Next(size int, sample LimitSample) int
+}
+
+type aimdLimit struct {
+	latencyThreshold time.Duration
+	failureRate      float64
+	backoff          float64
+}
+
+// NewAIMDLimit is a constructor for the aimdLimit.
+// It adds one worker after every healthy interval, and multiplies the number of workers by the backoff
+// when the average latency passes the latencyThreshold, or the share of failures passes the failureRate.
+// Zero thresholds disable the corresponding check. The backoff outside the (0, 1) range is replaced with 0.5.
+func NewAIMDLimit(latencyThreshold time.Duration, failureRate float64, backoff float64) ConcurrencyLimit {
+	if backoff <= 0 || backoff >= 1 {
+		backoff = 0.5
+	}
+
+	return aimdLimit{latencyThreshold: latencyThreshold, failureRate: failureRate, backoff: backoff}
+}
+
+// Next implements the ConcurrencyLimit interface for the aimdLimit.
+func (al aimdLimit) Next(size int, sample LimitSample) int {
+	overloaded := al.latencyThreshold > 0 && sample.AvgLatency > al.latencyThreshold
+	failing := al.failureRate > 0 && sample.FailureRate() > al.failureRate
+
+	if overloaded || failing {
+		return int(float64(size) * al.backoff)
+	}
+
+	return size + 1
+}
+
+type gradientLimit struct {
+	tolerance float64
+
+	mu          sync.Mutex
+	baseLatency time.Duration
+}
+
+// NewGradientLimit is a constructor for the gradientLimit.
+// It compares the average latency with the lowest one ever observed, and shrinks the number of workers
+// proportionally when the calls get slower than the tolerance allows, while the square root of the number of workers
+// is added on top to probe for the spare capacity. The tolerance of 0.5 lets the latency grow by half without penalty.
+// The limit remembers the observed latency, so it must not be shared between the pools.
+func NewGradientLimit(tolerance float64) ConcurrencyLimit {
+	return &gradientLimit{tolerance: max(tolerance, 0)}
+}
+
+// Next implements the ConcurrencyLimit interface for the gradientLimit.
+func (gl *gradientLimit) Next(size int, sample LimitSample) int {
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+
+	if gl.baseLatency == 0 || sample.MinLatency < gl.baseLatency {
+		gl.baseLatency = sample.MinLatency
+	}
+
+	if sample.AvgLatency <= 0 {
+		return size
+	}
+
+	gradient := float64(gl.baseLatency) * (1 + gl.tolerance) / float64(sample.AvgLatency)
+	gradient = min(max(gradient, 0.5), 1)
+
+	return int(float64(size)*gradient + math.Sqrt(float64(size)))
+}
+
+// AdaptivePoolConfig describes how the RunAdaptiveWorkerPool changes the number of workers.
+type AdaptivePoolConfig struct {
+	// MinSize is the minimal number of workers, the pool starts with it. The minimal value is 1.
+	MinSize int
+	// MaxSize is the maximal number of workers. It is raised to the MinSize if it is lower.
+	MaxSize int
+	// Interval is the period between the adjustments. The default value is 1 second.
+	Interval time.Duration
+	// Limit picks the number of workers. If it is nil, the NewAIMDLimit(0, 0.1, 0.5) is used.
+	Limit ConcurrencyLimit
+	// Clock is the source of time. If it is nil, the SystemClock is used.
+	Clock Clock
+	// OnResize is called every time the number of workers is changed. It is optional.
+	OnResize func(from, to int)
+}
+
+// RunAdaptiveWorkerPool starts the pool of goroutine workers to consume from the input channel and execute
+// the call functions with inputs, like the RunWorkerPool. Unlike it, the number of workers is adjusted
+// after every interval by the limit, based on the latency and the failures of the calls finished during it.
+// The intervals without the finished calls don't change the number of workers.
+func RunAdaptiveWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	cfg AdaptivePoolConfig,
+	bufSize int,
+	options ...CallOption[In, Out],
+) (<-chan Result[Out], error) {
+	return runAdaptiveWorkerPool(ctx, inCh, withOptions(call, options), cfg, bufSize)
+}
+
+func runAdaptiveWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	cfg AdaptivePoolConfig,
+	bufSize int,
+) (<-chan Result[Out], error) {
+	cfg.MinSize = max(cfg.MinSize, 1)
+	cfg.MaxSize = max(cfg.MaxSize, cfg.MinSize)
+
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+
+	if cfg.Limit == nil {
+		cfg.Limit = NewAIMDLimit(0, 0.1, 0.5)
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+
+	sampler := &limitSampler{}
+
+	var measured Call[In, Out]
+	if call != nil {
+		measured = func(ctx context.Context, in In) (Out, error) {
+			start := cfg.Clock.Now()
+			out, err := call(ctx, in)
+			sampler.record(cfg.Clock.Now().Sub(start), err)
+
+			return out, err
+		}
+	}
+
+	pool, err := newWorkerPool(ctx, inCh, measured, cfg.MinSize, bufSize)
+	if err != nil {
+		return nil, err
+	}
+
+	go adaptPoolSize(pool, sampler, cfg)
+
+	return pool.Results(), nil
+}
+
+// adaptPoolSize resizes the pool after every interval until all its workers are finished.
+func adaptPoolSize[In, Out any](pool *WorkerPool[In, Out], sampler *limitSampler, cfg AdaptivePoolConfig) {
+	size := cfg.MinSize
+
+	for {
+		select {
+		case <-pool.done:
+			return
+		case <-cfg.Clock.After(cfg.Interval):
+		}
+
+		sample, ok := sampler.take()
+		if !ok {
+			continue
+		}
+
+		next := min(max(cfg.Limit.Next(size, sample), cfg.MinSize), cfg.MaxSize)
+		if next == size {
+			continue
+		}
+
+		if pool.Resize(next) != nil {
+			return
+		}
+
+		if cfg.OnResize != nil {
+			cfg.OnResize(size, next)
+		}
+
+		size = next
+	}
+}
+
+// limitSampler collects the outcomes of the calls finished since the latest take.
+type limitSampler struct {
+	mu           sync.Mutex
+	calls        int
+	failures     int
+	totalLatency time.Duration
+	minLatency   time.Duration
+}
+
+func (ls *limitSampler) record(latency time.Duration, err error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.calls == 0 || latency < ls.minLatency {
+		ls.minLatency = latency
+	}
+
+	ls.calls++
+	ls.totalLatency += latency
+
+	if err != nil {
+		ls.failures++
+	}
+}
+
+// take returns the sample and resets the sampler. Returns false if there were no calls.
+func (ls *limitSampler) take() (LimitSample, bool) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.calls == 0 {
+		return LimitSample{}, false
+	}
+
+	sample := LimitSample{
+		Calls:      ls.calls,
+		Failures:   ls.failures,
+		AvgLatency: ls.totalLatency / time.Duration(ls.calls),
+		MinLatency: ls.minLatency,
+	}
+
+	ls.calls, ls.failures, ls.totalLatency, ls.minLatency = 0, 0, 0, 0
+
+	return sample, true
+}
@@ -0,0 +1,139 @@
+package merec_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestAIMDLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := merec.NewAIMDLimit(100*time.Millisecond, 0.2, 0.5)
+
+	testCases := map[string]struct {
+		givenSample merec.LimitSample
+		expSize     int
+	}{
+		"healthy": {
+			givenSample: merec.LimitSample{Calls: 10, Failures: 1, AvgLatency: 50 * time.Millisecond},
+			expSize:     9,
+		},
+		"slow": {
+			givenSample: merec.LimitSample{Calls: 10, AvgLatency: 150 * time.Millisecond},
+			expSize:     4,
+		},
+		"failing": {
+			givenSample: merec.LimitSample{Calls: 10, Failures: 3, AvgLatency: 50 * time.Millisecond},
+			expSize:     4,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expSize, limit.Next(8, tc.givenSample))
+		})
+	}
+}
+
+func TestGradientLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := merec.NewGradientLimit(0)
+
+	sample := merec.LimitSample{Calls: 10, AvgLatency: 10 * time.Millisecond, MinLatency: 10 * time.Millisecond}
+	require.Equal(t, 12, limit.Next(9, sample))
+
+	sample = merec.LimitSample{Calls: 10, AvgLatency: 40 * time.Millisecond, MinLatency: 20 * time.Millisecond}
+	require.Equal(t, 12, limit.Next(16, sample))
+}
+
+func TestRunAdaptiveWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	defer ctxCsl()
+
+	clock := newFakeClock()
+
+	var (
+		mu      sync.Mutex
+		resizes [][2]int
+	)
+
+	cfg := merec.AdaptivePoolConfig{
+		MinSize:  1,
+		MaxSize:  3,
+		Interval: time.Second,
+		Limit:    merec.NewAIMDLimit(0, 0, 0.5),
+		Clock:    clock,
+		OnResize: func(from, to int) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			resizes = append(resizes, [2]int{from, to})
+		},
+	}
+
+	resCh, err := merec.RunAdaptiveWorkerPool(ctx, endlessInputs(ctx), stabCall(0), cfg, 0)
+	require.NoError(t, err)
+
+	go func() {
+		for range resCh {
+		}
+	}()
+
+	resized := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(resizes)
+	}
+
+	for deadline := time.Now().Add(time.Second); resized() < 2 && time.Now().Before(deadline); {
+		if clock.Waiters() > 0 {
+			clock.Advance(cfg.Interval)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		require.Eventually(t, func() bool { return clock.Waiters() > 0 }, time.Second, time.Millisecond)
+		clock.Advance(cfg.Interval)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, [][2]int{{1, 2}, {2, 3}}, resizes)
+}
+
+// endlessInputs returns the channel producing the inputs until the context is done.
+func endlessInputs(ctx context.Context) <-chan string {
+	ch := make(chan string)
+
+	go func() {
+		defer close(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- "1":
+			}
+		}
+	}()
+
+	return ch
+}
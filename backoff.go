@@ -0,0 +1,96 @@
+package merec
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// noDelayCap is the maxDelay of the backoff strategies that is used when it is not positive.
+const noDelayCap = time.Duration(math.MaxInt64)
+
+// BackoffStrategy calculates the pause before the next retry attempt.
+type BackoffStrategy interface {
+	// Delay returns the pause before the retry number attempt, starting from 1.
+	// The prev is the pause used before the previous retry, it is zero for the first one.
+	Delay(attempt int, prev time.Duration) time.Duration
+}
+
+type constantBackoff struct {
+	delay time.Duration
+}
+
+// NewConstantBackoff is a constructor for the constantBackoff.
+// It always waits for the same delay between the attempts.
+func NewConstantBackoff(delay time.Duration) BackoffStrategy {
+	return constantBackoff{delay: delay}
+}
+
+// Delay implements the BackoffStrategy interface for the constantBackoff.
+func (cb constantBackoff) Delay(int, time.Duration) time.Duration {
+	return cb.delay
+}
+
+type exponentialBackoff struct {
+	base     time.Duration
+	maxDelay time.Duration
+}
+
+// NewExponentialBackoff is a constructor for the exponentialBackoff.
+// It doubles the delay with every attempt starting from base, but never waits longer than maxDelay.
+// The non-positive maxDelay doesn't limit the delay.
+func NewExponentialBackoff(base, maxDelay time.Duration) BackoffStrategy {
+	if maxDelay <= 0 {
+		maxDelay = noDelayCap
+	}
+
+	return exponentialBackoff{base: base, maxDelay: maxDelay}
+}
+
+// Delay implements the BackoffStrategy interface for the exponentialBackoff.
+func (eb exponentialBackoff) Delay(attempt int, _ time.Duration) time.Duration {
+	delay := eb.base
+
+	for i := 1; i < attempt && delay < eb.maxDelay; i++ {
+		if delay > eb.maxDelay/2 {
+			return eb.maxDelay
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, eb.maxDelay)
+}
+
+type decorrelatedJitterBackoff struct {
+	base     time.Duration
+	maxDelay time.Duration
+}
+
+// NewDecorrelatedJitterBackoff is a constructor for the decorrelatedJitterBackoff.
+// It picks a random delay between base and the tripled previous delay, but never waits longer than maxDelay.
+// The non-positive maxDelay doesn't limit the delay.
+func NewDecorrelatedJitterBackoff(base, maxDelay time.Duration) BackoffStrategy {
+	if maxDelay <= 0 {
+		maxDelay = noDelayCap
+	}
+
+	return decorrelatedJitterBackoff{base: base, maxDelay: maxDelay}
+}
+
+// Delay implements the BackoffStrategy interface for the decorrelatedJitterBackoff.
+func (djb decorrelatedJitterBackoff) Delay(_ int, prev time.Duration) time.Duration {
+	upper := noDelayCap
+	if prev < noDelayCap/3 {
+		upper = max(prev*3, djb.base)
+	}
+
+	if upper <= djb.base {
+		return min(djb.base, djb.maxDelay)
+	}
+
+	//nolint:gosec // reason: the jitter doesn't need the cryptographic randomness.
+	delay := djb.base + rand.N(upper-djb.base)
+
+	return min(delay, djb.maxDelay)
+}
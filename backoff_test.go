@@ -0,0 +1,86 @@
+package merec_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	t.Parallel()
+
+	backoff := merec.NewConstantBackoff(time.Second)
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		require.Equal(t, time.Second, backoff.Delay(attempt, time.Duration(attempt)*time.Second))
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenMaxDelay time.Duration
+		givenAttempt  int
+		expDelay      time.Duration
+	}{
+		"first":        {givenMaxDelay: 10 * time.Second, givenAttempt: 1, expDelay: time.Second},
+		"second":       {givenMaxDelay: 10 * time.Second, givenAttempt: 2, expDelay: 2 * time.Second},
+		"third":        {givenMaxDelay: 10 * time.Second, givenAttempt: 3, expDelay: 4 * time.Second},
+		"capped":       {givenMaxDelay: 10 * time.Second, givenAttempt: 5, expDelay: 10 * time.Second},
+		"huge":         {givenMaxDelay: 10 * time.Second, givenAttempt: 1000, expDelay: 10 * time.Second},
+		"no_cap":       {givenAttempt: 5, expDelay: 16 * time.Second},
+		"no_cap_huge":  {givenAttempt: 1000, expDelay: math.MaxInt64},
+		"negative_cap": {givenMaxDelay: -time.Second, givenAttempt: 2, expDelay: 2 * time.Second},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			backoff := merec.NewExponentialBackoff(time.Second, tc.givenMaxDelay)
+			require.Equal(t, tc.expDelay, backoff.Delay(tc.givenAttempt, 0))
+		})
+	}
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	t.Parallel()
+
+	base, maxDelay := 10*time.Millisecond, time.Second
+	backoff := merec.NewDecorrelatedJitterBackoff(base, maxDelay)
+
+	var prev time.Duration
+
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay := backoff.Delay(attempt, prev)
+		require.GreaterOrEqual(t, delay, base)
+		require.LessOrEqual(t, delay, maxDelay)
+		require.LessOrEqual(t, delay, max(prev*3, base))
+
+		prev = delay
+	}
+}
+
+func TestDecorrelatedJitterBackoff_NoCap(t *testing.T) {
+	t.Parallel()
+
+	base := 10 * time.Millisecond
+	backoff := merec.NewDecorrelatedJitterBackoff(base, 0)
+
+	delay := backoff.Delay(1, 0)
+	require.Equal(t, base, delay)
+
+	delay = backoff.Delay(2, time.Hour)
+	require.GreaterOrEqual(t, delay, base)
+	require.Less(t, delay, 3*time.Hour)
+
+	delay = backoff.Delay(3, math.MaxInt64)
+	require.GreaterOrEqual(t, delay, base)
+}
@@ -0,0 +1,172 @@
+package merec
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CacheConfig describes the Cache limits.
+type CacheConfig struct {
+	// TTL is the time the successful result is kept for. Zero means no expiration.
+	TTL time.Duration
+	// MaxEntries limits the number of entries, the least recently used one is evicted first. Zero means no limit.
+	MaxEntries int
+	// ErrorTTL is the time the error is kept for. Zero disables the caching of errors.
+	ErrorTTL time.Duration
+	// Clock is the source of time. If it is nil, the SystemClock is used.
+	Clock Clock
+}
+
+// CacheStats is the snapshot of the Cache counters.
+type CacheStats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+	Entries   int
+}
+
+type cacheEntry[K comparable, Out any] struct {
+	key       K
+	res       Result[Out]
+	expiresAt time.Time
+}
+
+// Cache keeps the results of the calls by key. It is safe for concurrent use,
+// so the same cache can be shared by several options to reuse the results between them.
+type Cache[K comparable, Out any] struct {
+	cfg CacheConfig
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	// lru keeps the entries from the most to the least recently used one.
+	lru       *list.List
+	hits      int
+	misses    int
+	evictions int
+}
+
+// NewCache is a constructor for the Cache.
+func NewCache[K comparable, Out any](cfg CacheConfig) *Cache[K, Out] {
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+
+	return &Cache[K, Out]{cfg: cfg, entries: make(map[K]*list.Element), lru: list.New()}
+}
+
+// Stats returns the current state of the cache.
+func (c *Cache[K, Out]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Entries: c.lru.Len()}
+}
+
+// Purge removes all the entries. The counters are kept.
+func (c *Cache[K, Out]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[K]*list.Element)
+	c.lru.Init()
+}
+
+// get returns the cached result of the key. Returns false if there is no entry, or it is expired.
+func (c *Cache[K, Out]) get(key K) (Result[Out], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if ok {
+		entry := elem.Value.(*cacheEntry[K, Out])
+
+		if entry.expiresAt.IsZero() || c.cfg.Clock.Now().Before(entry.expiresAt) {
+			c.hits++
+			c.lru.MoveToFront(elem)
+
+			return entry.res, true
+		}
+
+		c.remove(elem)
+	}
+
+	c.misses++
+
+	return Result[Out]{}, false
+}
+
+// set stores the result of the key, the error is stored only if the ErrorTTL is set.
+func (c *Cache[K, Out]) set(key K, res Result[Out]) {
+	ttl := c.cfg.TTL
+	if res.err != nil {
+		if c.cfg.ErrorTTL <= 0 {
+			return
+		}
+
+		ttl = c.cfg.ErrorTTL
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.cfg.Clock.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry[K, Out]{key: key, res: res, expiresAt: expiresAt}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	if c.cfg.MaxEntries > 0 && c.lru.Len() > c.cfg.MaxEntries {
+		c.remove(c.lru.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache[K, Out]) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry[K, Out]).key)
+}
+
+type cacheOption[In, Out any, K comparable] struct {
+	cache *Cache[K, Out]
+	keyOf func(In) K
+}
+
+// NewCacheOption is a constructor for the cacheOption.
+// The results are stored in the cache by the key returned by the keyOf. The value is shared as is,
+// so the callers must not modify it. The errors caused by the done context and the ErrMustStop are never cached.
+func NewCacheOption[In, Out any, K comparable](cache *Cache[K, Out], keyOf func(In) K) CallOption[In, Out] {
+	return cacheOption[In, Out, K]{cache: cache, keyOf: keyOf}
+}
+
+// WithOption implements the CallOption interface for the cacheOption.
+func (co cacheOption[In, Out, K]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		key := co.keyOf(in)
+
+		if res, ok := co.cache.get(key); ok {
+			return res.value, res.err
+		}
+
+		out, err := next(ctx, in)
+		if err == nil {
+			co.cache.set(key, ValueResult(out))
+		} else if CheckContext(ctx) == nil && !errors.Is(err, ErrMustStop) {
+			co.cache.set(key, ErrorResult[Out](err))
+		}
+
+		return out, err
+	}
+}
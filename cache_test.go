@@ -0,0 +1,75 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestCacheOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+
+	cache := merec.NewCache[string, int](merec.CacheConfig{
+		TTL:        time.Minute,
+		MaxEntries: 2,
+		ErrorTTL:   time.Second,
+		Clock:      clock,
+	})
+
+	call, calls := flakyCall(0, 0)
+	cached := merec.NewCacheOption[string, int](cache, func(in string) string { return in }).WithOption(call)
+
+	steps := []struct {
+		in       string
+		advance  time.Duration
+		expValue int
+		expErr   error
+		expCalls int32
+	}{
+		{in: "1", expValue: 1, expCalls: 1},
+		{in: "1", expValue: 1, expCalls: 1},
+		{in: "qwerty", expErr: strconv.ErrSyntax, expCalls: 2},
+		{in: "qwerty", expErr: strconv.ErrSyntax, expCalls: 2},
+		{in: "qwerty", advance: time.Second, expErr: strconv.ErrSyntax, expCalls: 3},
+		// The "2" evicts the "1", as the "qwerty" was used later.
+		{in: "2", expValue: 2, expCalls: 4},
+		{in: "1", expValue: 1, expCalls: 5},
+		{in: "1", advance: time.Minute, expValue: 1, expCalls: 6},
+	}
+
+	for i, step := range steps {
+		clock.Advance(step.advance)
+
+		out, err := cached(ctx, step.in)
+		require.ErrorIs(t, err, step.expErr, i)
+		require.Equal(t, step.expValue, out, i)
+		require.Equal(t, step.expCalls, calls.Load(), i)
+	}
+
+	require.Equal(t, merec.CacheStats{Hits: 2, Misses: 6, Evictions: 2, Entries: 2}, cache.Stats())
+
+	cache.Purge()
+	require.Zero(t, cache.Stats().Entries)
+}
+
+func TestCacheOption_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	cache := merec.NewCache[string, int](merec.CacheConfig{ErrorTTL: time.Minute})
+	cached := merec.NewCacheOption[string, int](cache, func(in string) string { return in }).WithOption(stabCall(time.Hour))
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	ctxCsl()
+
+	_, err := cached(ctx, "1")
+	require.ErrorIs(t, err, errCtxCancel)
+	require.Zero(t, cache.Stats().Entries)
+}
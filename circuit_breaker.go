@@ -0,0 +1,204 @@
+package merec
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the CircuitBreaker.
+type CircuitState int
+
+// The list of the CircuitBreaker states.
+const (
+	// CircuitClosed lets all the calls through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all the calls.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe calls through.
+	CircuitHalfOpen
+)
+
+// String implements io.Stringer interface.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig describes the CircuitBreaker transitions.
+type CircuitBreakerConfig struct {
+	// FailureThreshold opens the circuit after that many consecutive failures. The minimal value is 1.
+	FailureThreshold int
+	// CoolDown is the time the circuit stays open before letting the probe calls through.
+	CoolDown time.Duration
+	// HalfOpenProbes is the number of the probe calls in the half-open state.
+	// The circuit gets closed when all of them succeed. The minimal value is 1.
+	HalfOpenProbes int
+	// OnStateChange is called on every state transition. It is optional.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker implements the closed/open/half-open state machine shared by all the calls wrapped with it.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu             sync.Mutex
+	state          CircuitState
+	generation     uint64
+	failures       int
+	openedAt       time.Time
+	probes         int
+	probeSuccesses int
+}
+
+// NewCircuitBreaker is a constructor for the CircuitBreaker.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	cfg.FailureThreshold = max(cfg.FailureThreshold, 1)
+	cfg.HalfOpenProbes = max(cfg.HalfOpenProbes, 1)
+
+	return &CircuitBreaker{cfg: cfg}
+}
+
+// State returns the current state of the circuit.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.cfg.CoolDown {
+		return CircuitHalfOpen
+	}
+
+	return cb.state
+}
+
+// allow checks if the call can be executed. It returns the generation of the state the call was admitted in.
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+
+	var from CircuitState
+
+	changed := false
+
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= cb.cfg.CoolDown {
+		from, changed = cb.state, true
+		cb.setState(CircuitHalfOpen)
+	}
+
+	var err error
+
+	switch cb.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.probes >= cb.cfg.HalfOpenProbes {
+			err = ErrCircuitOpen
+		} else {
+			cb.probes++
+		}
+	case CircuitClosed:
+	}
+
+	generation := cb.generation
+
+	cb.mu.Unlock()
+
+	if changed {
+		cb.notify(from, CircuitHalfOpen)
+	}
+
+	return generation, err
+}
+
+// record registers the outcome of the call admitted in the generation.
+func (cb *CircuitBreaker) record(generation uint64, err error) {
+	cb.mu.Lock()
+
+	if generation != cb.generation {
+		cb.mu.Unlock()
+		return
+	}
+
+	from := cb.state
+
+	switch {
+	case err != nil && cb.state == CircuitHalfOpen:
+		cb.setState(CircuitOpen)
+	case err != nil:
+		cb.failures++
+		if cb.failures >= cb.cfg.FailureThreshold {
+			cb.setState(CircuitOpen)
+		}
+	case cb.state == CircuitHalfOpen:
+		cb.probeSuccesses++
+		if cb.probeSuccesses >= cb.cfg.HalfOpenProbes {
+			cb.setState(CircuitClosed)
+		}
+	default:
+		cb.failures = 0
+	}
+
+	to := cb.state
+
+	cb.mu.Unlock()
+
+	if from != to {
+		cb.notify(from, to)
+	}
+}
+
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	cb.state = state
+	cb.generation++
+	cb.failures = 0
+	cb.probes = 0
+	cb.probeSuccesses = 0
+
+	if state == CircuitOpen {
+		cb.openedAt = time.Now()
+	}
+}
+
+func (cb *CircuitBreaker) notify(from, to CircuitState) {
+	if cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(from, to)
+	}
+}
+
+type circuitBreakerOption[In, Out any] struct {
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerOption is a constructor for the circuitBreakerOption.
+// The same breaker can be passed to several options to share the circuit state.
+func NewCircuitBreakerOption[In, Out any](breaker *CircuitBreaker) CallOption[In, Out] {
+	return circuitBreakerOption[In, Out]{breaker: breaker}
+}
+
+// WithOption implements the CallOption interface for the circuitBreakerOption.
+// While the circuit is open, the calls are rejected with the ErrCircuitOpen without the execution.
+func (cbo circuitBreakerOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		generation, err := cbo.breaker.allow()
+		if err != nil {
+			return *new(Out), err
+		}
+
+		out, err := next(ctx, in)
+		cbo.breaker.record(generation, err)
+
+		if err != nil {
+			return *new(Out), err
+		}
+
+		return out, nil
+	}
+}
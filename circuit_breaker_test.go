@@ -0,0 +1,152 @@
+package merec_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	const coolDown = 50 * time.Millisecond
+
+	var (
+		mu          sync.Mutex
+		transitions []merec.CircuitState
+	)
+
+	breaker := merec.NewCircuitBreaker(merec.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         coolDown,
+		HalfOpenProbes:   2,
+		OnStateChange: func(_, to merec.CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			transitions = append(transitions, to)
+		},
+	})
+	call, calls := flakyCall(0, 0)
+	call = merec.NewCircuitBreakerOption[string, int](breaker).WithOption(call)
+
+	ctx := context.Background()
+
+	_, err := call(ctx, "qwerty")
+	require.NotErrorIs(t, err, merec.ErrCircuitOpen)
+	require.Equal(t, merec.CircuitClosed, breaker.State())
+
+	_, err = call(ctx, "1")
+	require.NoError(t, err)
+
+	_, err = call(ctx, "qwerty")
+	require.NotErrorIs(t, err, merec.ErrCircuitOpen)
+	require.Equal(t, merec.CircuitClosed, breaker.State(), "a success resets the consecutive failures")
+
+	_, err = call(ctx, "qwerty")
+	require.NotErrorIs(t, err, merec.ErrCircuitOpen)
+	require.Equal(t, merec.CircuitOpen, breaker.State())
+
+	_, err = call(ctx, "1")
+	require.ErrorIs(t, err, merec.ErrCircuitOpen)
+	require.Equal(t, int32(4), calls.Load(), "the open circuit doesn't call the downstream")
+
+	time.Sleep(coolDown)
+	require.Equal(t, merec.CircuitHalfOpen, breaker.State())
+
+	_, err = call(ctx, "qwerty")
+	require.NotErrorIs(t, err, merec.ErrCircuitOpen)
+	require.Equal(t, merec.CircuitOpen, breaker.State(), "a failed probe opens the circuit again")
+
+	time.Sleep(coolDown)
+
+	_, err = call(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, merec.CircuitHalfOpen, breaker.State())
+
+	_, err = call(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, merec.CircuitClosed, breaker.State())
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, []merec.CircuitState{
+		merec.CircuitOpen,
+		merec.CircuitHalfOpen,
+		merec.CircuitOpen,
+		merec.CircuitHalfOpen,
+		merec.CircuitClosed,
+	}, transitions)
+}
+
+func TestCircuitBreaker_HalfOpenProbesLimit(t *testing.T) {
+	t.Parallel()
+
+	breaker := merec.NewCircuitBreaker(merec.CircuitBreakerConfig{FailureThreshold: 1, HalfOpenProbes: 1})
+	ctx := context.Background()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	call := merec.NewCircuitBreakerOption[string, int](breaker).WithOption(
+		func(ctx context.Context, in string) (int, error) {
+			if in == "slow" {
+				close(started)
+				<-release
+			}
+
+			return stabCall(0)(ctx, in)
+		},
+	)
+
+	_, err := call(ctx, "qwerty")
+	require.NotErrorIs(t, err, merec.ErrCircuitOpen)
+
+	probeDone := make(chan error)
+
+	go func() {
+		_, err := call(ctx, "slow")
+		probeDone <- err
+	}()
+
+	<-started
+
+	_, err = call(ctx, "1")
+	require.ErrorIs(t, err, merec.ErrCircuitOpen, "only one probe is allowed")
+
+	close(release)
+	require.NotErrorIs(t, <-probeDone, merec.ErrCircuitOpen)
+}
+
+func TestCircuitBreaker_WithRunFromChan(t *testing.T) {
+	t.Parallel()
+
+	breaker := merec.NewCircuitBreaker(merec.CircuitBreakerConfig{FailureThreshold: 1, CoolDown: time.Hour})
+	option := merec.NewCircuitBreakerOption[string, int](breaker)
+
+	inCh := make(chan string, 3)
+	inCh <- "qwerty"
+	inCh <- "1"
+	inCh <- "2"
+	close(inCh)
+
+	resCh, err := merec.RunFromChan(context.Background(), inCh, stabCall(0), option)
+	require.NoError(t, err)
+
+	var rejected int
+
+	for r := range resCh {
+		require.ErrorIs(t, r.Err(), merec.ErrBusinessLogic)
+
+		if errors.Is(r.Err(), merec.ErrCircuitOpen) {
+			rejected++
+		}
+	}
+
+	require.Equal(t, 2, rejected)
+}
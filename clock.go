@@ -0,0 +1,26 @@
+package merec
+
+import "time"
+
+// Clock is the source of time for the time-dependent options. It allows replacing the real time in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+// SystemClock is the Clock implementation backed by the real time.
+var SystemClock Clock = systemClock{}
+
+// Now implements the Clock interface for the systemClock.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements the Clock interface for the systemClock.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
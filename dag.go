@@ -0,0 +1,262 @@
+package merec
+
+import (
+	"context"
+	"fmt"
+)
+
+// DAGFailurePolicy defines the behavior of the DAG when the task fails.
+type DAGFailurePolicy int
+
+// The list of supported failure policies.
+const (
+	// DAGSkipDependents skips all the tasks depending on the failed one, the rest of the tasks keep running.
+	DAGSkipDependents DAGFailurePolicy = iota
+	// DAGCancelAll cancels the tasks in flight and skips all the tasks not started yet.
+	DAGCancelAll
+	// DAGContinue runs the dependents of the failed task anyway, without its output.
+	DAGContinue
+)
+
+type dagTask[Out any] struct {
+	name string
+	deps []string
+	call Call[map[string]Out, Out]
+}
+
+// DAG is the graph of tasks, every task runs once all its dependencies are completed,
+// and receives their outputs keyed by the task names. It is not safe to add tasks concurrently.
+type DAG[Out any] struct {
+	tasks map[string]*dagTask[Out]
+	// order keeps the tasks in the order they were added, so the scheduling is deterministic.
+	order []string
+}
+
+// NewDAG is a constructor for the DAG.
+func NewDAG[Out any]() *DAG[Out] {
+	return &DAG[Out]{tasks: make(map[string]*dagTask[Out])}
+}
+
+// AddTask adds the task with the unique name, executing the call with the outputs of the deps.
+// The dependencies may be added later, they are checked by the Validate.
+func (d *DAG[Out]) AddTask(
+	name string,
+	deps []string,
+	call Call[map[string]Out, Out],
+	options ...CallOption[map[string]Out, Out],
+) error {
+	if call == nil {
+		return ErrNilCallFunc
+	}
+
+	if _, ok := d.tasks[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateTask, name)
+	}
+
+	d.tasks[name] = &dagTask[Out]{name: name, deps: deps, call: withOptions(call, options)}
+	d.order = append(d.order, name)
+
+	return nil
+}
+
+// Validate checks that all the dependencies are defined, and returns the CycleError if the graph has a cycle.
+func (d *DAG[Out]) Validate() error {
+	for _, name := range d.order {
+		for _, dep := range d.tasks[name].deps {
+			if _, ok := d.tasks[dep]; !ok {
+				return fmt.Errorf("%w: %s depends on %s", ErrUnknownTask, name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(d.tasks))
+
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return &CycleError{Path: append(append([]string{}, path[i:]...), name)}
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dep := range d.tasks[name].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range d.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type dagDone[Out any] struct {
+	name string
+	res  Result[Out]
+}
+
+// Run validates the graph and executes the tasks with up to parallelism of them at the same time.
+// Returns the Result of every task when all of them are completed. The skipped tasks get the ErrTaskSkipped.
+// The ErrMustStop of any task cancels the whole run regardless of the policy.
+// Once the context is done, the tasks not started yet get the ErrCtxCancel or ErrCtxDeadline result.
+func (d *DAG[Out]) Run(
+	ctx context.Context,
+	parallelism int,
+	policy DAGFailurePolicy,
+) (map[string]Result[Out], error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	if parallelism < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	runCtx, runCtxCsl := context.WithCancel(ctx)
+	defer runCtxCsl()
+
+	remaining := make(map[string]int, len(d.tasks))
+	dependents := make(map[string][]string, len(d.tasks))
+
+	for _, name := range d.order {
+		task := d.tasks[name]
+		remaining[name] = len(task.deps)
+
+		for _, dep := range task.deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	results := make(map[string]Result[Out], len(d.tasks))
+	doneCh := make(chan dagDone[Out], len(d.tasks))
+
+	var (
+		ready    []string
+		running  int
+		canceled bool
+	)
+
+	for _, name := range d.order {
+		if remaining[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	var complete func(name string, res Result[Out])
+	complete = func(name string, res Result[Out]) {
+		results[name] = res
+
+		for _, dependent := range dependents[name] {
+			remaining[dependent]--
+			if remaining[dependent] > 0 {
+				continue
+			}
+
+			if failed := d.failedDep(dependent, results); failed != "" && policy != DAGContinue {
+				complete(dependent, ErrorResult[Out](fmt.Errorf("%w: %s failed", ErrTaskSkipped, failed)))
+				continue
+			}
+
+			ready = append(ready, dependent)
+		}
+	}
+
+	for len(results) < len(d.tasks) {
+		for len(ready) > 0 && (running < parallelism || canceled || CheckContext(ctx) != nil) {
+			name := ready[0]
+			ready = ready[1:]
+
+			if err := CheckContext(ctx); err != nil {
+				complete(name, ErrorResult[Out](err))
+				continue
+			}
+
+			if canceled {
+				complete(name, ErrorResult[Out](fmt.Errorf("%w: the run is canceled", ErrTaskSkipped)))
+				continue
+			}
+
+			running++
+
+			go func(task *dagTask[Out], deps map[string]Out) {
+				res, mustStop := execute(runCtx, task.call, deps)
+				if mustStop {
+					runCtxCsl()
+				}
+
+				doneCh <- dagDone[Out]{name: task.name, res: res}
+			}(d.tasks[name], d.depOutputs(name, results))
+		}
+
+		if running == 0 {
+			break
+		}
+
+		done := <-doneCh
+		running--
+
+		if done.res.err != nil && (policy == DAGCancelAll || CheckContext(runCtx) != nil) {
+			canceled = true
+
+			runCtxCsl()
+		}
+
+		complete(done.name, done.res)
+	}
+
+	return results, nil
+}
+
+// failedDep returns the name of the first failed dependency of the task, or the empty string.
+func (d *DAG[Out]) failedDep(name string, results map[string]Result[Out]) string {
+	for _, dep := range d.tasks[name].deps {
+		if results[dep].err != nil {
+			return dep
+		}
+	}
+
+	return ""
+}
+
+// depOutputs collects the outputs of the successful dependencies of the task.
+func (d *DAG[Out]) depOutputs(name string, results map[string]Result[Out]) map[string]Out {
+	deps := make(map[string]Out, len(d.tasks[name].deps))
+
+	for _, dep := range d.tasks[name].deps {
+		if res := results[dep]; res.err == nil {
+			deps[dep] = res.value
+		}
+	}
+
+	return deps
+}
@@ -0,0 +1,190 @@
+package merec_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+// sumDeps returns the task that adds up the outputs of its dependencies and the value.
+func sumDeps(value int) merec.Call[map[string]int, int] {
+	return func(_ context.Context, deps map[string]int) (int, error) {
+		sum := value
+		for _, v := range deps {
+			sum += v
+		}
+
+		return sum, nil
+	}
+}
+
+func failTask(context.Context, map[string]int) (int, error) {
+	return 0, errFlaky
+}
+
+// diamondDAG builds the graph a -> (b, c) -> d, and e independent of them.
+func diamondDAG(t *testing.T, b merec.Call[map[string]int, int]) *merec.DAG[int] {
+	t.Helper()
+
+	dag := merec.NewDAG[int]()
+	require.NoError(t, dag.AddTask("d", []string{"b", "c"}, sumDeps(1000)))
+	require.NoError(t, dag.AddTask("a", nil, sumDeps(1)))
+	require.NoError(t, dag.AddTask("b", []string{"a"}, b))
+	require.NoError(t, dag.AddTask("c", []string{"a"}, sumDeps(100)))
+	require.NoError(t, dag.AddTask("e", nil, sumDeps(5)))
+
+	return dag
+}
+
+func TestDAG_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenB      merec.Call[map[string]int, int]
+		givenPolicy merec.DAGFailurePolicy
+		expValues   map[string]int
+		expErrs     map[string]error
+	}{
+		"success": {
+			givenB:    sumDeps(10),
+			expValues: map[string]int{"a": 1, "b": 11, "c": 101, "d": 1112, "e": 5},
+		},
+		"skip_dependents": {
+			givenB:      failTask,
+			givenPolicy: merec.DAGSkipDependents,
+			expValues:   map[string]int{"a": 1, "c": 101, "e": 5},
+			expErrs:     map[string]error{"b": errFlaky, "d": merec.ErrTaskSkipped},
+		},
+		"continue": {
+			givenB:      failTask,
+			givenPolicy: merec.DAGContinue,
+			expValues:   map[string]int{"a": 1, "c": 101, "d": 1101, "e": 5},
+			expErrs:     map[string]error{"b": errFlaky},
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			results, err := diamondDAG(t, tc.givenB).Run(ctx, 2, tc.givenPolicy)
+			require.NoError(t, err)
+			require.Len(t, results, 5)
+
+			for name, res := range results {
+				if expErr, ok := tc.expErrs[name]; ok {
+					require.ErrorIs(t, res.Err(), expErr, name)
+					continue
+				}
+
+				require.NoError(t, res.Err(), name)
+				require.Equal(t, tc.expValues[name], res.Value(), name)
+			}
+		})
+	}
+}
+
+func TestDAG_CancelAll(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		started []string
+	)
+
+	track := func(name string, call merec.Call[map[string]int, int]) merec.Call[map[string]int, int] {
+		return func(ctx context.Context, deps map[string]int) (int, error) {
+			mu.Lock()
+			started = append(started, name)
+			mu.Unlock()
+
+			return call(ctx, deps)
+		}
+	}
+
+	slow := func(ctx context.Context, _ map[string]int) (int, error) {
+		return stabCall(time.Hour)(ctx, "1")
+	}
+
+	dag := merec.NewDAG[int]()
+	require.NoError(t, dag.AddTask("slow", nil, track("slow", slow)))
+	require.NoError(t, dag.AddTask("fail", nil, track("fail", failTask)))
+	require.NoError(t, dag.AddTask("next", []string{"fail"}, track("next", sumDeps(1))))
+	require.NoError(t, dag.AddTask("later", nil, track("later", sumDeps(1))))
+
+	results, err := dag.Run(context.Background(), 2, merec.DAGCancelAll)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, results["slow"].Err(), errCtxCancel)
+	require.ErrorIs(t, results["fail"].Err(), errFlaky)
+	require.ErrorIs(t, results["next"].Err(), merec.ErrTaskSkipped)
+	require.ErrorIs(t, results["later"].Err(), merec.ErrTaskSkipped)
+
+	sort.Strings(started)
+	require.Equal(t, []string{"fail", "slow"}, started)
+}
+
+func TestDAG_Parallelism(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning atomic.Int32
+
+	task := func(context.Context, map[string]int) (int, error) {
+		cur := running.Add(1)
+		defer running.Add(-1)
+
+		if cur > maxRunning.Load() {
+			maxRunning.Store(cur)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		return 0, nil
+	}
+
+	dag := merec.NewDAG[int]()
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		require.NoError(t, dag.AddTask(name, nil, task))
+	}
+
+	results, err := dag.Run(context.Background(), 2, merec.DAGSkipDependents)
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+	require.LessOrEqual(t, maxRunning.Load(), int32(2))
+}
+
+func TestDAG_Validate(t *testing.T) {
+	t.Parallel()
+
+	dag := merec.NewDAG[int]()
+	require.NoError(t, dag.AddTask("a", []string{"c"}, sumDeps(1)))
+	require.NoError(t, dag.AddTask("b", []string{"a"}, sumDeps(1)))
+	require.NoError(t, dag.AddTask("c", []string{"b"}, sumDeps(1)))
+	require.ErrorIs(t, dag.AddTask("c", nil, sumDeps(1)), merec.ErrDuplicateTask)
+	require.ErrorIs(t, dag.AddTask("d", nil, nil), merec.ErrNilCallFunc)
+
+	err := dag.Validate()
+
+	var cycleErr *merec.CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.ErrorIs(t, err, merec.ErrDAGCycle)
+	require.Equal(t, []string{"a", "c", "b", "a"}, cycleErr.Path)
+
+	_, err = dag.Run(context.Background(), 1, merec.DAGSkipDependents)
+	require.ErrorIs(t, err, merec.ErrDAGCycle)
+
+	dag = merec.NewDAG[int]()
+	require.NoError(t, dag.AddTask("a", []string{"missing"}, sumDeps(1)))
+	require.ErrorIs(t, dag.Validate(), merec.ErrUnknownTask)
+}
@@ -0,0 +1,182 @@
+package merec
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadLetter is the input the call failed with, together with the error.
+type DeadLetter[In any] struct {
+	Input In
+	Err   error
+	At    time.Time
+}
+
+// DeadLetterSink receives the failed inputs.
+type DeadLetterSink[In any] interface {
+	// Send stores the dead letter. It is called concurrently by the workers.
+	Send(ctx context.Context, letter DeadLetter[In]) error
+}
+
+// MemoryDeadLetterSink keeps the dead letters in memory. It is safe for concurrent use.
+type MemoryDeadLetterSink[In any] struct {
+	mu      sync.Mutex
+	letters []DeadLetter[In]
+}
+
+// NewMemoryDeadLetterSink is a constructor for the MemoryDeadLetterSink.
+func NewMemoryDeadLetterSink[In any]() *MemoryDeadLetterSink[In] {
+	return &MemoryDeadLetterSink[In]{}
+}
+
+// Send implements the DeadLetterSink interface for the MemoryDeadLetterSink.
+func (s *MemoryDeadLetterSink[In]) Send(_ context.Context, letter DeadLetter[In]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = append(s.letters, letter)
+
+	return nil
+}
+
+// Letters returns the copy of the received dead letters in the order they were sent.
+func (s *MemoryDeadLetterSink[In]) Letters() []DeadLetter[In] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]DeadLetter[In](nil), s.letters...)
+}
+
+// deadLetterRecord is the NDJSON line of the FileDeadLetterSink.
+type deadLetterRecord[In any] struct {
+	Input In        `json:"input"`
+	Err   string    `json:"error"`
+	At    time.Time `json:"at"`
+}
+
+// FileDeadLetterSink appends the dead letters to the file, one JSON object per line.
+// The inputs must be serializable with the encoding/json. It is safe for concurrent use.
+type FileDeadLetterSink[In any] struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileDeadLetterSink is a constructor for the FileDeadLetterSink.
+// The file is created if it doesn't exist, the existing letters are kept.
+func NewFileDeadLetterSink[In any](path string) (*FileDeadLetterSink[In], error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileDeadLetterSink[In]{file: file}, nil
+}
+
+// Send implements the DeadLetterSink interface for the FileDeadLetterSink.
+func (s *FileDeadLetterSink[In]) Send(_ context.Context, letter DeadLetter[In]) error {
+	line, err := json.Marshal(deadLetterRecord[In]{Input: letter.Input, Err: letter.Err.Error(), At: letter.At})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.file.Write(append(line, '\n'))
+
+	return err
+}
+
+// Close closes the file.
+func (s *FileDeadLetterSink[In]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// Replay reads the inputs from the file written by the FileDeadLetterSink.
+// Returns the channel to be passed to the runner, it is closed when all the inputs are sent or the context is done.
+// The whole file is decoded before the first input is sent, so the broken file is reported without side effects.
+func Replay[In any](ctx context.Context, path string) (<-chan In, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var inputs []In
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<24)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record deadLetterRecord[In]
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		inputs = append(inputs, record.Input)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	inCh := make(chan In)
+
+	go func() {
+		defer close(inCh)
+
+		for _, in := range inputs {
+			if !sendContext(ctx, inCh, in) {
+				return
+			}
+		}
+	}()
+
+	return inCh, nil
+}
+
+type deadLetterOption[In, Out any] struct {
+	sink DeadLetterSink[In]
+}
+
+// NewDeadLetterOption is a constructor for the deadLetterOption.
+// The inputs of the failed calls are sent to the sink together with the error. Whatever its position is,
+// the runners apply it after all the other options, so it receives the inputs that failed after the retries,
+// the rejections of the options, and the recovered panics. If the sink fails, its error is joined with the call error.
+func NewDeadLetterOption[In, Out any](sink DeadLetterSink[In]) CallOption[In, Out] {
+	return deadLetterOption[In, Out]{sink: sink}
+}
+
+// WithOption implements the CallOption interface for the deadLetterOption.
+func (dlo deadLetterOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		out, err := next(ctx, in)
+		if err == nil {
+			return out, nil
+		}
+
+		letter := DeadLetter[In]{Input: in, Err: err, At: time.Now()}
+		if sinkErr := dlo.sink.Send(context.WithoutCancel(ctx), letter); sinkErr != nil {
+			return *new(Out), errors.Join(err, sinkErr)
+		}
+
+		return *new(Out), err
+	}
+}
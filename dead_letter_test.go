@@ -0,0 +1,120 @@
+package merec_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func failedInputsCh() chan string {
+	ch := make(chan string, 4)
+	for _, in := range []string{"1", "qwerty", "2", "asdf"} {
+		ch <- in
+	}
+	close(ch)
+
+	return ch
+}
+
+func TestDeadLetterOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sink := merec.NewMemoryDeadLetterSink[string]()
+
+	resCh, err := merec.RunWorkerPool(ctx, failedInputsCh(), stabCall(0), 2, 0,
+		merec.NewRetryOption[string, int](merec.NewConstantBackoff(0), 2, 0),
+		merec.NewDeadLetterOption[string, int](sink),
+	)
+	require.NoError(t, err)
+
+	failures := 0
+
+	for res := range resCh {
+		if res.Err() != nil {
+			failures++
+		}
+	}
+
+	require.Equal(t, 2, failures)
+
+	letters := sink.Letters()
+	inputs := make([]string, 0, len(letters))
+
+	for _, letter := range letters {
+		require.Error(t, letter.Err)
+		require.False(t, letter.At.IsZero())
+
+		inputs = append(inputs, letter.Input)
+	}
+
+	require.ElementsMatch(t, []string{"qwerty", "asdf"}, inputs)
+}
+
+func TestFileDeadLetterSink_Replay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dead_letters.ndjson")
+
+	sink, err := merec.NewFileDeadLetterSink[string](path)
+	require.NoError(t, err)
+
+	resCh, err := merec.RunFromChan(ctx, failedInputsCh(), stabCall(0), merec.NewDeadLetterOption[string, int](sink))
+	require.NoError(t, err)
+
+	for range resCh {
+	}
+
+	require.NoError(t, sink.Close())
+
+	inCh, err := merec.Replay[string](ctx, path)
+	require.NoError(t, err)
+
+	replayed := make([]string, 0, 2)
+
+	for in := range inCh {
+		replayed = append(replayed, in)
+	}
+
+	require.Equal(t, []string{"qwerty", "asdf"}, replayed)
+
+	_, err = merec.Replay[int](ctx, path)
+	require.ErrorContains(t, err, "line 1")
+}
+
+func TestDeadLetterOption_AfterAllOptions(t *testing.T) {
+	t.Parallel()
+
+	sink := merec.NewMemoryDeadLetterSink[string]()
+	breaker := merec.NewCircuitBreaker(merec.CircuitBreakerConfig{FailureThreshold: 1, CoolDown: time.Hour})
+
+	inCh := make(chan string, 2)
+	inCh <- "panic"
+	inCh <- "1"
+	close(inCh)
+
+	resCh, err := merec.RunFromChan(context.Background(), inCh, panickyCall,
+		merec.NewDeadLetterOption[string, int](sink),
+		merec.NewCircuitBreakerOption[string, int](breaker),
+	)
+	require.NoError(t, err)
+
+	for res := range resCh {
+		require.Error(t, res.Err())
+	}
+
+	letters := sink.Letters()
+	require.Len(t, letters, 2)
+
+	require.Equal(t, "panic", letters[0].Input)
+	require.ErrorIs(t, letters[0].Err, merec.ErrPanic)
+
+	require.Equal(t, "1", letters[1].Input)
+	require.ErrorIs(t, letters[1].Err, merec.ErrCircuitOpen)
+}
@@ -0,0 +1,543 @@
+package merec
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentPrefix  = "segment-"
+	segmentSuffix  = ".log"
+	checkpointName = "checkpoint"
+
+	defaultSegmentSize = 64 << 20
+)
+
+// DurableQueueConfig describes the DurableQueue storage.
+type DurableQueueConfig struct {
+	// SegmentSize is the size of the log file after which the new one is started. The default value is 64 MiB.
+	SegmentSize int64
+	// Sync flushes every enqueued item to the disk before the Enqueue returns.
+	Sync bool
+}
+
+// QueueItem is the item of the DurableQueue with its offset, the offset is used to acknowledge it.
+type QueueItem[T any] struct {
+	Offset uint64
+	Value  T
+}
+
+type queueSegment struct {
+	base uint64
+	path string
+}
+
+// DurableQueue is the persistent FIFO queue backed by the append-only segment log in the directory.
+// The values are stored as JSON lines, so they must be serializable with the encoding/json.
+// The consumed items are acknowledged one by one, and the committed offset is the one before which all the items
+// are acknowledged. After the restart, the consuming is resumed from the committed offset, so the items
+// that were not acknowledged are delivered again. The item that is never acknowledged holds the committed offset,
+// so the acknowledgements after it are kept in memory, the segments are not compacted, and all the following items
+// are delivered again after every restart. It is safe for concurrent use.
+type DurableQueue[T any] struct {
+	dir string
+	cfg DurableQueueConfig
+
+	mu        sync.Mutex
+	segments  []queueSegment
+	active    *os.File
+	size      int64
+	next      uint64
+	committed uint64
+	acked     map[uint64]struct{}
+	// notify is closed and replaced on every Enqueue to wake up the following consumers.
+	notify chan struct{}
+	closed chan struct{}
+	// readErr is the error that interrupted the latest Consume.
+	readErr error
+}
+
+// OpenDurableQueue opens the queue stored in the directory, or creates the new one.
+// The item partially written before the crash is discarded.
+func OpenDurableQueue[T any](dir string, cfg DurableQueueConfig) (*DurableQueue[T], error) {
+	if cfg.SegmentSize <= 0 {
+		cfg.SegmentSize = defaultSegmentSize
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	q := &DurableQueue[T]{
+		dir:    dir,
+		cfg:    cfg,
+		acked:  make(map[uint64]struct{}),
+		notify: make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+
+	if err := q.recover(); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// recover restores the state of the queue from the files and opens the active segment.
+func (q *DurableQueue[T]) recover() error {
+	committed, err := readCheckpoint(filepath.Join(q.dir, checkpointName))
+	if err != nil {
+		return err
+	}
+
+	q.committed = committed
+
+	q.segments, err = listSegments(q.dir)
+	if err != nil {
+		return err
+	}
+
+	if len(q.segments) == 0 {
+		return q.roll(committed)
+	}
+
+	last := q.segments[len(q.segments)-1]
+
+	count, size, err := countItems(last.path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Truncate(last.path, size); err != nil {
+		return err
+	}
+
+	q.next = max(last.base+count, committed)
+
+	if q.next > last.base+count {
+		return q.roll(q.next)
+	}
+
+	q.active, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	q.size = size
+
+	return err
+}
+
+// Enqueue appends the value to the queue and returns its offset.
+func (q *DurableQueue[T]) Enqueue(value T) (uint64, error) {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.isClosed() {
+		return 0, ErrQueueClosed
+	}
+
+	if q.size >= q.cfg.SegmentSize {
+		if err := q.roll(q.next); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := q.active.Write(append(line, '\n'))
+	if err != nil {
+		// The partial line is removed, otherwise the next item is appended to it, and the log gets corrupted.
+		if n > 0 && q.active.Truncate(q.size) != nil {
+			return 0, errors.Join(err, q.roll(q.next))
+		}
+
+		return 0, err
+	}
+
+	q.size += int64(n)
+
+	if q.cfg.Sync {
+		if err := q.active.Sync(); err != nil {
+			return 0, err
+		}
+	}
+
+	offset := q.next
+	q.next++
+
+	close(q.notify)
+	q.notify = make(chan struct{})
+
+	return offset, nil
+}
+
+// Consume starts a separate goroutine to read the items starting from the committed offset.
+// Returns the channel to be passed to the runner. Without the follow, the channel is closed after the last item
+// enqueued so far, otherwise it waits for the new items until the context is done or the queue is closed.
+// Every call starts from the committed offset, so there must be a single consumer at a time.
+// If the log can't be read, the channel is closed early, and the error is reported by the Err.
+func (q *DurableQueue[T]) Consume(ctx context.Context, follow bool) (<-chan QueueItem[T], error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	q.mu.Lock()
+	from, closed := q.committed, q.isClosed()
+	q.readErr = nil
+	q.mu.Unlock()
+
+	if closed {
+		return nil, ErrQueueClosed
+	}
+
+	outCh := make(chan QueueItem[T])
+
+	go func() {
+		defer close(outCh)
+
+		if err := q.read(ctx, from, follow, outCh); err != nil {
+			q.mu.Lock()
+			q.readErr = err
+			q.mu.Unlock()
+		}
+	}()
+
+	return outCh, nil
+}
+
+// Err returns the error that interrupted the latest Consume, the ErrCorruptedQueue wraps it.
+// It must be checked once the channel is closed, to tell the early end from the end of the queue.
+func (q *DurableQueue[T]) Err() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.readErr
+}
+
+// Ack acknowledges the item, and moves the committed offset forward if all the previous items are acknowledged.
+func (q *DurableQueue[T]) Ack(offset uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if offset < q.committed {
+		return nil
+	}
+
+	if offset >= q.next {
+		return fmt.Errorf("%w: %d", ErrUnknownOffset, offset)
+	}
+
+	q.acked[offset] = struct{}{}
+
+	committed := q.committed
+	for _, ok := q.acked[committed]; ok; _, ok = q.acked[committed] {
+		delete(q.acked, committed)
+		committed++
+	}
+
+	if committed == q.committed {
+		return nil
+	}
+
+	if err := writeCheckpoint(filepath.Join(q.dir, checkpointName), committed); err != nil {
+		return err
+	}
+
+	q.committed = committed
+
+	return q.compact()
+}
+
+// Committed returns the offset before which all the items are acknowledged.
+func (q *DurableQueue[T]) Committed() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.committed
+}
+
+// Len returns the number of the items that are not committed yet.
+func (q *DurableQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return int(q.next - q.committed)
+}
+
+// Close stops the following consumers and closes the log. The queue can be opened again.
+func (q *DurableQueue[T]) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.isClosed() {
+		return nil
+	}
+
+	close(q.closed)
+
+	return q.active.Close()
+}
+
+// read sends the items starting from the offset into the channel.
+// Returns the error if the log can't be read, the end of the consuming is not an error.
+func (q *DurableQueue[T]) read(ctx context.Context, offset uint64, follow bool, outCh chan<- QueueItem[T]) error {
+	var (
+		file    *os.File
+		reader  *bufio.Reader
+		segment queueSegment
+	)
+
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	for ; ; offset++ {
+		seg, ok := q.waitFor(ctx, offset, follow)
+		if !ok {
+			return nil
+		}
+
+		if file == nil || seg.base != segment.base {
+			if file != nil {
+				file.Close()
+			}
+
+			var err error
+
+			file, err = os.Open(seg.path)
+			if err != nil {
+				return fmt.Errorf("%w: offset %d: %w", ErrCorruptedQueue, offset, err)
+			}
+
+			segment, reader = seg, bufio.NewReader(file)
+
+			for skip := seg.base; skip < offset; skip++ {
+				if _, err := reader.ReadBytes('\n'); err != nil {
+					return fmt.Errorf("%w: offset %d: %w", ErrCorruptedQueue, skip, err)
+				}
+			}
+		}
+
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return fmt.Errorf("%w: offset %d: %w", ErrCorruptedQueue, offset, err)
+		}
+
+		var value T
+		if err := json.Unmarshal(line, &value); err != nil {
+			return fmt.Errorf("%w: offset %d: %w", ErrCorruptedQueue, offset, err)
+		}
+
+		if !sendContext(ctx, outCh, QueueItem[T]{Offset: offset, Value: value}) {
+			return nil
+		}
+	}
+}
+
+// waitFor waits until the item with the offset is enqueued, and returns the segment containing it.
+// Returns false if the consuming must be stopped.
+func (q *DurableQueue[T]) waitFor(ctx context.Context, offset uint64, follow bool) (queueSegment, bool) {
+	for {
+		q.mu.Lock()
+		next, notify := q.next, q.notify
+		segments := q.segments
+		q.mu.Unlock()
+
+		if offset < next {
+			i, found := slices.BinarySearchFunc(segments, offset, func(s queueSegment, o uint64) int {
+				return cmp.Compare(s.base, o)
+			})
+			if !found {
+				i--
+			}
+
+			if i < 0 {
+				return queueSegment{}, false
+			}
+
+			return segments[i], true
+		}
+
+		if !follow {
+			return queueSegment{}, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return queueSegment{}, false
+		case <-q.closed:
+			return queueSegment{}, false
+		case <-notify:
+		}
+	}
+}
+
+// roll starts the new segment with the base offset. It must be called with the mutex locked.
+func (q *DurableQueue[T]) roll(base uint64) error {
+	if q.active != nil {
+		if err := q.active.Close(); err != nil {
+			return err
+		}
+	}
+
+	path := filepath.Join(q.dir, fmt.Sprintf("%s%020d%s", segmentPrefix, base, segmentSuffix))
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	q.segments = append(slices.Clip(q.segments), queueSegment{base: base, path: path})
+	q.active, q.size, q.next = file, 0, base
+
+	return nil
+}
+
+// compact removes the segments with all the items committed. It must be called with the mutex locked.
+func (q *DurableQueue[T]) compact() error {
+	removed := 0
+
+	for removed < len(q.segments)-1 && q.segments[removed+1].base <= q.committed {
+		if err := os.Remove(q.segments[removed].path); err != nil {
+			return err
+		}
+
+		removed++
+	}
+
+	q.segments = slices.Clone(q.segments[removed:])
+
+	return nil
+}
+
+func (q *DurableQueue[T]) isClosed() bool {
+	select {
+	case <-q.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func listSegments(dir string) ([]queueSegment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []queueSegment
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		base, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCorruptedQueue, name)
+		}
+
+		segments = append(segments, queueSegment{base: base, path: filepath.Join(dir, name)})
+	}
+
+	slices.SortFunc(segments, func(a, b queueSegment) int {
+		return cmp.Compare(a.base, b.base)
+	})
+
+	return segments, nil
+}
+
+// countItems returns the number of the complete lines in the file and their total size.
+func countItems(path string) (uint64, int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end := bytes.LastIndexByte(data, '\n') + 1
+
+	return uint64(bytes.Count(data[:end], []byte{'\n'})), int64(end), nil
+}
+
+func readCheckpoint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	offset, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCorruptedQueue, err)
+	}
+
+	return offset, nil
+}
+
+// writeCheckpoint replaces the checkpoint atomically, so the crash never leaves it half-written.
+func writeCheckpoint(path string, offset uint64) error {
+	tmp := path + ".tmp"
+
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(file, strconv.FormatUint(offset, 10)); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// AckResult acknowledges the queue item the successful result was produced from. It is meant to be called
+// by the consumer of the results once the result is handled, so the crash before that delivers the item again.
+// The failed results are not acknowledged, so they hold the committed offset and are redelivered after the restart.
+// The item that always fails holds it forever, so once such an item is stored elsewhere, for example,
+// by the NewDeadLetterOption, it must be acknowledged directly with the DurableQueue.Ack.
+// The result must be produced with the NewMetadataOption, otherwise the ErrNoQueueItem is returned.
+func AckResult[T, Out any](queue *DurableQueue[T], res Result[Out]) error {
+	meta, ok := res.Meta()
+	if !ok {
+		return ErrNoQueueItem
+	}
+
+	item, ok := meta.Input.(QueueItem[T])
+	if !ok {
+		return ErrNoQueueItem
+	}
+
+	if res.err != nil {
+		return nil
+	}
+
+	return queue.Ack(item.Offset)
+}
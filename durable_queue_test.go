@@ -0,0 +1,273 @@
+package merec_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func queueCall(fail string) merec.Call[merec.QueueItem[string], int] {
+	return func(_ context.Context, item merec.QueueItem[string]) (int, error) {
+		if item.Value == fail {
+			return 0, errFlaky
+		}
+
+		return strconv.Atoi(item.Value)
+	}
+}
+
+func enqueueAll(t *testing.T, queue *merec.DurableQueue[string], values ...string) {
+	t.Helper()
+
+	for _, value := range values {
+		_, err := queue.Enqueue(value)
+		require.NoError(t, err)
+	}
+}
+
+func TestDurableQueue_ResumeAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	queue, err := merec.OpenDurableQueue[string](dir, merec.DurableQueueConfig{SegmentSize: 8})
+	require.NoError(t, err)
+
+	enqueueAll(t, queue, "1", "2", "3", "4", "5", "6")
+
+	itemCh, err := queue.Consume(ctx, false)
+	require.NoError(t, err)
+
+	resCh, err := merec.RunWorkerPool(ctx, itemCh, queueCall("4"), 2, 0,
+		merec.NewMetadataOption[merec.QueueItem[string], int](nil))
+	require.NoError(t, err)
+
+	for res := range resCh {
+		require.NoError(t, merec.AckResult(queue, res))
+	}
+
+	require.NoError(t, queue.Err())
+
+	require.Equal(t, uint64(3), queue.Committed())
+	require.Equal(t, 3, queue.Len())
+	require.NoError(t, queue.Close())
+
+	queue, err = merec.OpenDurableQueue[string](dir, merec.DurableQueueConfig{SegmentSize: 8})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, queue.Close()) })
+
+	itemCh, err = queue.Consume(ctx, false)
+	require.NoError(t, err)
+
+	resCh, err = merec.RunWorkerPool(ctx, itemCh, queueCall(""), 2, 0,
+		merec.NewMetadataOption[merec.QueueItem[string], int](nil))
+	require.NoError(t, err)
+
+	values := make([]int, 0, 3)
+
+	for res := range resCh {
+		require.NoError(t, res.Err())
+
+		values = append(values, res.Value())
+
+		require.Less(t, queue.Committed(), uint64(6), "the result is not acknowledged before it is consumed")
+		require.NoError(t, merec.AckResult(queue, res))
+	}
+
+	require.ElementsMatch(t, []int{4, 5, 6}, values)
+	require.Equal(t, uint64(6), queue.Committed())
+	require.Zero(t, queue.Len())
+
+	segments, err := filepath.Glob(filepath.Join(dir, "segment-*.log"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+}
+
+func TestDurableQueue_AckDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	queue, err := merec.OpenDurableQueue[string](t.TempDir(), merec.DurableQueueConfig{SegmentSize: 8})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, queue.Close()) })
+
+	enqueueAll(t, queue, "1", "2", "3", "4", "5", "6")
+
+	itemCh, err := queue.Consume(ctx, false)
+	require.NoError(t, err)
+
+	sink := merec.NewMemoryDeadLetterSink[merec.QueueItem[string]]()
+
+	resCh, err := merec.RunWorkerPool(ctx, itemCh, queueCall("2"), 2, 0,
+		merec.NewMetadataOption[merec.QueueItem[string], int](nil),
+		merec.NewDeadLetterOption[merec.QueueItem[string], int](sink),
+	)
+	require.NoError(t, err)
+
+	for res := range resCh {
+		if res.Err() == nil {
+			require.NoError(t, merec.AckResult(queue, res))
+			continue
+		}
+
+		// The failed item is in the dead-letter sink, so it no longer holds the committed offset.
+		meta, ok := res.Meta()
+		require.True(t, ok)
+		require.NoError(t, queue.Ack(meta.Input.(merec.QueueItem[string]).Offset))
+	}
+
+	letters := sink.Letters()
+	require.Len(t, letters, 1)
+	require.Equal(t, merec.QueueItem[string]{Offset: 1, Value: "2"}, letters[0].Input)
+
+	require.Equal(t, uint64(6), queue.Committed())
+	require.Zero(t, queue.Len())
+}
+
+func TestDurableQueue_PartialWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	queue, err := merec.OpenDurableQueue[string](dir, merec.DurableQueueConfig{})
+	require.NoError(t, err)
+
+	enqueueAll(t, queue, "1", "2")
+	require.NoError(t, queue.Close())
+
+	segments, err := filepath.Glob(filepath.Join(dir, "segment-*.log"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+
+	file, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+
+	_, err = file.WriteString(`"3`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	queue, err = merec.OpenDurableQueue[string](dir, merec.DurableQueueConfig{})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, queue.Close()) })
+
+	offset, err := queue.Enqueue("4")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), offset)
+
+	itemCh, err := queue.Consume(ctx, false)
+	require.NoError(t, err)
+
+	values := make([]string, 0, 3)
+	for item := range itemCh {
+		values = append(values, item.Value)
+	}
+
+	require.Equal(t, []string{"1", "2", "4"}, values)
+}
+
+func TestDurableQueue_Follow(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	queue, err := merec.OpenDurableQueue[string](t.TempDir(), merec.DurableQueueConfig{})
+	require.NoError(t, err)
+
+	itemCh, err := queue.Consume(ctx, true)
+	require.NoError(t, err)
+
+	enqueueAll(t, queue, "1")
+	require.Equal(t, merec.QueueItem[string]{Offset: 0, Value: "1"}, <-itemCh)
+
+	enqueueAll(t, queue, "2")
+	require.Equal(t, merec.QueueItem[string]{Offset: 1, Value: "2"}, <-itemCh)
+
+	require.NoError(t, queue.Close())
+
+	_, ok := <-itemCh
+	require.False(t, ok)
+
+	_, err = queue.Enqueue("3")
+	require.ErrorIs(t, err, merec.ErrQueueClosed)
+}
+
+func TestDurableQueue_Ack(t *testing.T) {
+	t.Parallel()
+
+	queue, err := merec.OpenDurableQueue[string](t.TempDir(), merec.DurableQueueConfig{})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, queue.Close()) })
+
+	enqueueAll(t, queue, "1", "2", "3")
+
+	require.NoError(t, queue.Ack(1))
+	require.Zero(t, queue.Committed())
+
+	require.NoError(t, queue.Ack(0))
+	require.Equal(t, uint64(2), queue.Committed())
+
+	require.NoError(t, queue.Ack(0))
+	require.ErrorIs(t, queue.Ack(3), merec.ErrUnknownOffset)
+}
+
+func TestDurableQueue_CorruptedLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	queue, err := merec.OpenDurableQueue[int](dir, merec.DurableQueueConfig{})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, queue.Close()) })
+
+	for _, value := range []int{1, 2, 3} {
+		_, err := queue.Enqueue(value)
+		require.NoError(t, err)
+	}
+
+	segments, err := filepath.Glob(filepath.Join(dir, "segment-*.log"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+
+	// The second item is damaged in place, keeping the line structure.
+	require.NoError(t, os.WriteFile(segments[0], []byte("1\nx\n3\n"), 0o644))
+
+	itemCh, err := queue.Consume(ctx, false)
+	require.NoError(t, err)
+
+	values := make([]int, 0, 1)
+	for item := range itemCh {
+		values = append(values, item.Value)
+	}
+
+	require.Equal(t, []int{1}, values)
+	require.ErrorIs(t, queue.Err(), merec.ErrCorruptedQueue)
+	require.ErrorContains(t, queue.Err(), "offset 1")
+}
+
+func TestAckResult_NoMetadata(t *testing.T) {
+	t.Parallel()
+
+	queue, err := merec.OpenDurableQueue[string](t.TempDir(), merec.DurableQueueConfig{})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, queue.Close()) })
+
+	require.ErrorIs(t, merec.AckResult(queue, merec.ValueResult(1)), merec.ErrNoQueueItem)
+}
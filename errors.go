@@ -1,14 +1,127 @@
 package merec
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // The list of supported errors.
 var (
-	ErrBusinessLogic = errors.New("business logic execution failed")
-	ErrCtxCancel     = errors.New("root context was canceled")
-	ErrCtxDeadline   = errors.New("root context's deadline passed")
-	ErrNilContext    = errors.New("context must be initiated")
-	ErrNilInChan     = errors.New("input channel must be initiated")
-	ErrNilCallFunc   = errors.New("call function must be initiated")
-	ErrMustStop      = errors.New("the processing must be interrupted")
+	ErrBusinessLogic      = errors.New("business logic execution failed")
+	ErrCtxCancel          = errors.New("root context was canceled")
+	ErrCtxDeadline        = errors.New("root context's deadline passed")
+	ErrNilContext         = errors.New("context must be initiated")
+	ErrNilInChan          = errors.New("input channel must be initiated")
+	ErrNilCallFunc        = errors.New("call function must be initiated")
+	ErrNilKeyFunc         = errors.New("key function must be initiated")
+	ErrNilPriorityFunc    = errors.New("priority function must be initiated")
+	ErrMustStop           = errors.New("the processing must be interrupted")
+	ErrCircuitOpen        = errors.New("the circuit is open")
+	ErrCostExceedsBurst   = errors.New("the call cost exceeds the rate limiter burst")
+	ErrPanic              = errors.New("the call panicked")
+	ErrReorderWindowFull  = errors.New("the reorder window is full")
+	ErrInvalidPoolSize    = errors.New("pool size must be positive")
+	ErrExecutorClosed     = errors.New("the executor is closed")
+	ErrExecutorFull       = errors.New("the executor queue is full")
+	ErrNoFutures          = errors.New("at least one future must be provided")
+	ErrWorkerPoolFinished = errors.New("the worker pool is finished")
+	ErrBatchSizeMismatch  = errors.New("the batch call returned the wrong number of values")
+	ErrDuplicateTask      = errors.New("the task is already defined")
+	ErrUnknownTask        = errors.New("the task dependency is not defined")
+	ErrDAGCycle           = errors.New("the task graph contains a cycle")
+	ErrTaskSkipped        = errors.New("the task was skipped")
+	ErrQueueClosed        = errors.New("the queue is closed")
+	ErrUnknownOffset      = errors.New("the offset is not enqueued")
+	ErrCorruptedQueue     = errors.New("the queue storage is corrupted")
+	ErrNoQueueItem        = errors.New("the result is not produced from the queue item with the metadata")
+	ErrNilPoolControl     = errors.New("the pool control must be provided")
+	ErrPoolControlInUse   = errors.New("the pool control is already bound to a pool")
+	ErrInvalidRate        = errors.New("the rate must be positive")
+	ErrInvalidBurst       = errors.New("the burst must be at least 1")
 )
+
+// FailFastError is returned when the FailFastBudget is exhausted. It matches the ErrMustStop.
+type FailFastError struct {
+	// Failures is the number of failures in the window that exhausted the budget.
+	Failures int
+	// Calls is the number of calls in the window that exhausted the budget.
+	Calls int
+	// Err is the call error, it is nil if the call was rejected without the execution.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FailFastError) Error() string {
+	msg := fmt.Sprintf("%v: %d failures in %d calls", ErrMustStop, e.Failures, e.Calls)
+	if e.Err == nil {
+		return msg
+	}
+
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+// Unwrap returns the ErrMustStop and the call error.
+func (e *FailFastError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrMustStop}
+	}
+
+	return []error{ErrMustStop, e.Err}
+}
+
+// PanicError is returned when the call panics. It matches the ErrPanic.
+type PanicError struct {
+	// Value is the value passed to the panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the moment of the panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPanic, e.Value)
+}
+
+// Unwrap returns the ErrPanic and the panic value if it is an error.
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+
+	return []error{ErrPanic}
+}
+
+// CycleError is returned when the DAG tasks depend on each other in a cycle. It matches the ErrDAGCycle.
+type CycleError struct {
+	// Path is the list of the tasks forming the cycle, the first task is repeated at the end.
+	Path []string
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrDAGCycle, strings.Join(e.Path, " -> "))
+}
+
+// Unwrap returns the ErrDAGCycle.
+func (e *CycleError) Unwrap() error {
+	return ErrDAGCycle
+}
+
+// FallbackError is returned when both the primary call and its fallback fail. It matches both errors.
+type FallbackError struct {
+	// Primary is the error of the primary call.
+	Primary error
+	// Fallback is the error of the fallback call.
+	Fallback error
+}
+
+// Error implements the error interface.
+func (e *FallbackError) Error() string {
+	return fmt.Sprintf("fallback failed: %v: primary failed: %v", e.Fallback, e.Primary)
+}
+
+// Unwrap returns the fallback and the primary errors.
+func (e *FallbackError) Unwrap() []error {
+	return []error{e.Fallback, e.Primary}
+}
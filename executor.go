@@ -0,0 +1,180 @@
+package merec
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type executorTask[In, Out any] struct {
+	ctx     context.Context
+	in      In
+	resolve func(Result[Out])
+}
+
+// Executor is the long-lived bounded pool of goroutine workers shared by many independent producers.
+// Unlike the RunWorkerPool, the inputs are submitted one by one and every one of them gets its own Future.
+// The ErrMustStop doesn't stop the Executor, it affects only the Result of the corresponding input.
+type Executor[In, Out any] struct {
+	call   Call[In, Out]
+	taskCh chan executorTask[In, Out]
+	seq    atomic.Uint64
+
+	// ctx is canceled when the graceful shutdown takes too long.
+	ctx    context.Context
+	ctxCsl context.CancelFunc
+
+	mu          sync.RWMutex
+	closed      bool
+	closing     chan struct{}
+	closingOnce sync.Once
+	workersDone chan struct{}
+}
+
+// NewExecutor starts the pool of poolSize goroutine workers executing the call with the submitted inputs.
+// The bufSize is the number of submitted inputs that can wait for a free worker.
+func NewExecutor[In, Out any](
+	call Call[In, Out],
+	poolSize int,
+	bufSize int,
+	options ...CallOption[In, Out],
+) (*Executor[In, Out], error) {
+	if call == nil {
+		return nil, ErrNilCallFunc
+	}
+
+	if poolSize < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+
+	e := &Executor[In, Out]{
+		call:        withOptions(call, options),
+		taskCh:      make(chan executorTask[In, Out], bufSize),
+		ctx:         ctx,
+		ctxCsl:      ctxCsl,
+		closing:     make(chan struct{}),
+		workersDone: make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(poolSize)
+
+	for i := 0; i < poolSize; i++ {
+		go func(id int) {
+			defer wg.Done()
+
+			for task := range e.taskCh {
+				e.run(id, task)
+			}
+		}(i)
+	}
+
+	go func() {
+		wg.Wait()
+		ctxCsl()
+		close(e.workersDone)
+	}()
+
+	return e, nil
+}
+
+// Submit waits for the room in the Executor queue and submits the input.
+// The context limits both the waiting and the call execution.
+func (e *Executor[In, Out]) Submit(ctx context.Context, in In) (Future[Out], error) {
+	if ctx == nil {
+		return Future[Out]{}, ErrNilContext
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return Future[Out]{}, ErrExecutorClosed
+	}
+
+	future, resolve := newFuture[Out]()
+
+	select {
+	case e.taskCh <- executorTask[In, Out]{ctx: ctx, in: in, resolve: resolve}:
+		return future, nil
+	case <-ctx.Done():
+		return Future[Out]{}, CheckContext(ctx)
+	case <-e.closing:
+		return Future[Out]{}, ErrExecutorClosed
+	}
+}
+
+// TrySubmit submits the input if there is room in the Executor queue, otherwise returns the ErrExecutorFull.
+// The context limits the call execution.
+func (e *Executor[In, Out]) TrySubmit(ctx context.Context, in In) (Future[Out], error) {
+	if ctx == nil {
+		return Future[Out]{}, ErrNilContext
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return Future[Out]{}, ErrExecutorClosed
+	}
+
+	future, resolve := newFuture[Out]()
+
+	select {
+	case e.taskCh <- executorTask[In, Out]{ctx: ctx, in: in, resolve: resolve}:
+		return future, nil
+	default:
+		return Future[Out]{}, ErrExecutorFull
+	}
+}
+
+// Close stops accepting new inputs and waits for all the submitted ones to complete.
+func (e *Executor[In, Out]) Close() error {
+	return e.Shutdown(context.Background())
+}
+
+// Shutdown stops accepting new inputs and waits for the submitted ones to complete until the context is done.
+// After that, it returns the context error right away, the calls in flight are canceled, and the inputs
+// still waiting in the queue are resolved with the ErrCtxCancel without the execution.
+// The Futures of the calls ignoring the cancellation are resolved only when the calls return.
+func (e *Executor[In, Out]) Shutdown(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+
+	e.closingOnce.Do(func() {
+		close(e.closing)
+
+		e.mu.Lock()
+		e.closed = true
+		close(e.taskCh)
+		e.mu.Unlock()
+	})
+
+	select {
+	case <-e.workersDone:
+		return nil
+	case <-ctx.Done():
+		e.ctxCsl()
+		return CheckContext(ctx)
+	}
+}
+
+func (e *Executor[In, Out]) run(id int, task executorTask[In, Out]) {
+	if err := CheckContext(e.ctx); err != nil {
+		task.resolve(ErrorResult[Out](err))
+		return
+	}
+
+	ctx, ctxCsl := context.WithCancel(task.ctx)
+	defer ctxCsl()
+
+	stop := context.AfterFunc(e.ctx, ctxCsl)
+	defer stop()
+
+	res, _ := execute(withWorker(ctx, &e.seq, id), e.call, task.in)
+	task.resolve(res)
+}
@@ -0,0 +1,236 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestExecutor(t *testing.T) {
+	t.Parallel()
+
+	const (
+		producers = 4
+		inputs    = 25
+	)
+
+	executor, err := merec.NewExecutor(stabCall(time.Millisecond), 3, 2)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	futures := make([][]merec.Future[int], producers)
+
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+
+		go func(p int) {
+			defer wg.Done()
+
+			for i := 0; i < inputs; i++ {
+				future, err := executor.Submit(context.Background(), strconv.Itoa(p*inputs+i))
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				futures[p] = append(futures[p], future)
+			}
+		}(p)
+	}
+
+	wg.Wait()
+	require.NoError(t, executor.Close())
+
+	for p := range futures {
+		require.Len(t, futures[p], inputs)
+
+		for i, future := range futures[p] {
+			<-future.Done()
+
+			res, ok := future.Result()
+			require.True(t, ok)
+			require.NoError(t, res.Err())
+			require.Equal(t, p*inputs+i, res.Value())
+		}
+	}
+}
+
+func TestExecutor_TrySubmit(t *testing.T) {
+	t.Parallel()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	call := func(ctx context.Context, in string) (int, error) {
+		if in == "0" {
+			close(started)
+			<-release
+		}
+
+		return stabCall(0)(ctx, in)
+	}
+
+	executor, err := merec.NewExecutor[string, int](call, 1, 1)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	first, err := executor.TrySubmit(ctx, "0")
+	require.NoError(t, err)
+
+	<-started
+
+	second, err := executor.TrySubmit(ctx, "1")
+	require.NoError(t, err)
+
+	_, err = executor.TrySubmit(ctx, "2")
+	require.ErrorIs(t, err, merec.ErrExecutorFull)
+
+	_, ok := first.Result()
+	require.False(t, ok)
+
+	close(release)
+	require.NoError(t, executor.Close())
+
+	for i, future := range []merec.Future[int]{first, second} {
+		res, ok := future.Result()
+		require.True(t, ok)
+		require.Equal(t, merec.ValueResult(i), res)
+	}
+}
+
+func TestExecutor_SubmitContextDone(t *testing.T) {
+	t.Parallel()
+
+	executor, err := merec.NewExecutor(stabCall(time.Hour), 1, 0)
+	require.NoError(t, err)
+
+	busyCtx, busyCtxCsl := context.WithCancel(context.Background())
+	busy, err := executor.Submit(busyCtx, "1")
+	require.NoError(t, err)
+
+	ctx, ctxCsl := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer ctxCsl()
+
+	_, err = executor.Submit(ctx, "2")
+	require.ErrorIs(t, err, merec.ErrCtxDeadline)
+
+	busyCtxCsl()
+	<-busy.Done()
+
+	res, _ := busy.Result()
+	require.ErrorIs(t, res.Err(), errCtxCancel)
+	require.NoError(t, executor.Close())
+}
+
+func TestExecutor_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	executor, err := merec.NewExecutor(stabCall(time.Hour), 1, 1)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	inFlight, err := executor.Submit(ctx, "1")
+	require.NoError(t, err)
+
+	queued, err := executor.Submit(ctx, "2")
+	require.NoError(t, err)
+
+	shutdownCtx, shutdownCtxCsl := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer shutdownCtxCsl()
+
+	require.ErrorIs(t, executor.Shutdown(shutdownCtx), merec.ErrCtxDeadline)
+
+	<-inFlight.Done()
+	<-queued.Done()
+
+	res, ok := inFlight.Result()
+	require.True(t, ok)
+	require.ErrorIs(t, res.Err(), merec.ErrBusinessLogic)
+	require.ErrorIs(t, res.Err(), errCtxCancel)
+
+	res, ok = queued.Result()
+	require.True(t, ok)
+	require.ErrorIs(t, res.Err(), merec.ErrCtxCancel)
+
+	_, err = executor.Submit(ctx, "3")
+	require.ErrorIs(t, err, merec.ErrExecutorClosed)
+
+	_, err = executor.TrySubmit(ctx, "3")
+	require.ErrorIs(t, err, merec.ErrExecutorClosed)
+
+	require.NoError(t, executor.Close(), "the repeated close is a no-op")
+}
+
+func TestExecutor_ShutdownIgnoredCancel(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	executor, err := merec.NewExecutor(func(_ context.Context, in string) (int, error) {
+		<-release
+		return strconv.Atoi(in)
+	}, 1, 0)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	inFlight, err := executor.Submit(ctx, "1")
+	require.NoError(t, err)
+
+	shutdownCtx, shutdownCtxCsl := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer shutdownCtxCsl()
+
+	start := time.Now()
+
+	require.ErrorIs(t, executor.Shutdown(shutdownCtx), merec.ErrCtxDeadline)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+
+	_, ok := inFlight.Result()
+	require.False(t, ok, "the call ignoring the cancellation is still in flight")
+
+	close(release)
+
+	<-inFlight.Done()
+
+	res, _ := inFlight.Result()
+	require.NoError(t, res.Err())
+	require.Equal(t, 1, res.Value())
+}
+
+func TestNewExecutor_ValidationFail(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenCall     merec.Call[string, int]
+		givenPoolSize int
+		expErr        error
+	}{
+		"nil_call_function": {
+			givenPoolSize: 1,
+			expErr:        merec.ErrNilCallFunc,
+		},
+		"zero_pool_size": {
+			givenCall: stabCall(time.Second),
+			expErr:    merec.ErrInvalidPoolSize,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			executor, err := merec.NewExecutor(tc.givenCall, tc.givenPoolSize, 0)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Nil(t, executor)
+		})
+	}
+}
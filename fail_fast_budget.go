@@ -0,0 +1,165 @@
+package merec
+
+import (
+	"sync"
+	"time"
+)
+
+// FailFastConfig describes when the FailFastBudget gets exhausted.
+// The window is the set of the latest calls the limits are checked against, it is limited by the WindowSize and
+// the WindowPeriod. If both of them are zero, the window covers all the calls.
+type FailFastConfig struct {
+	// MistakesLimit exhausts the budget when the window contains that many failures. Zero disables the check.
+	MistakesLimit int
+	// WindowSize limits the window to the number of the latest calls. Zero means no limit.
+	WindowSize int
+	// WindowPeriod limits the window to the calls finished within the latest period. Zero means no limit.
+	WindowPeriod time.Duration
+	// FailureRate exhausts the budget when the share of failures in the window passes it. Zero disables the check.
+	FailureRate float64
+	// MinCalls is the minimal number of calls in the window required to check the FailureRate.
+	MinCalls int
+}
+
+// FailFastStats is the snapshot of the FailFastBudget state.
+type FailFastStats struct {
+	Calls          int
+	Failures       int
+	WindowCalls    int
+	WindowFailures int
+	Exhausted      bool
+}
+
+type callOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// FailFastBudget is the error budget shared by all the calls wrapped with the same option.
+// It is safe for concurrent use.
+type FailFastBudget struct {
+	cfg FailFastConfig
+
+	mu             sync.Mutex
+	window         []callOutcome
+	calls          int
+	failures       int
+	windowFailures int
+	exhaustedBy    *FailFastError
+}
+
+// NewFailFastBudget is a constructor for the FailFastBudget.
+func NewFailFastBudget(cfg FailFastConfig) *FailFastBudget {
+	return &FailFastBudget{cfg: cfg}
+}
+
+// Stats returns the current state of the budget.
+func (b *FailFastBudget) Stats() FailFastStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.evict(time.Now())
+
+	return b.stats()
+}
+
+// exhausted returns the error the budget was exhausted with, or nil if there is still some budget left.
+func (b *FailFastBudget) exhausted() *FailFastError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.exhaustedBy
+}
+
+// record registers the call outcome and returns the error if this call exhausted the budget.
+func (b *FailFastBudget) record(err error) *FailFastError {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.exhaustedBy != nil {
+		if err == nil {
+			return nil
+		}
+
+		return &FailFastError{Failures: b.exhaustedBy.Failures, Calls: b.exhaustedBy.Calls, Err: err}
+	}
+
+	now := time.Now()
+	failed := err != nil
+
+	b.calls++
+
+	if failed {
+		b.failures++
+		b.windowFailures++
+	}
+
+	if b.windowed() {
+		b.window = append(b.window, callOutcome{at: now, failed: failed})
+	}
+
+	b.evict(now)
+
+	if !failed || !b.overLimit() {
+		return nil
+	}
+
+	stats := b.stats()
+	b.exhaustedBy = &FailFastError{Failures: stats.WindowFailures, Calls: stats.WindowCalls, Err: err}
+
+	return b.exhaustedBy
+}
+
+func (b *FailFastBudget) windowed() bool {
+	return b.cfg.WindowSize > 0 || b.cfg.WindowPeriod > 0
+}
+
+func (b *FailFastBudget) evict(now time.Time) {
+	var evicted int
+
+	for _, o := range b.window {
+		tooMany := b.cfg.WindowSize > 0 && len(b.window)-evicted > b.cfg.WindowSize
+		tooOld := b.cfg.WindowPeriod > 0 && now.Sub(o.at) > b.cfg.WindowPeriod
+
+		if !tooMany && !tooOld {
+			break
+		}
+
+		if o.failed {
+			b.windowFailures--
+		}
+
+		evicted++
+	}
+
+	b.window = b.window[evicted:]
+}
+
+func (b *FailFastBudget) overLimit() bool {
+	stats := b.stats()
+
+	if b.cfg.MistakesLimit > 0 && stats.WindowFailures >= b.cfg.MistakesLimit {
+		return true
+	}
+
+	if b.cfg.FailureRate <= 0 || stats.WindowCalls == 0 || stats.WindowCalls < b.cfg.MinCalls {
+		return false
+	}
+
+	return float64(stats.WindowFailures)/float64(stats.WindowCalls) > b.cfg.FailureRate
+}
+
+func (b *FailFastBudget) stats() FailFastStats {
+	windowCalls := b.calls
+	if b.windowed() {
+		windowCalls = len(b.window)
+	}
+
+	return FailFastStats{
+		Calls:          b.calls,
+		Failures:       b.failures,
+		WindowCalls:    windowCalls,
+		WindowFailures: b.windowFailures,
+		Exhausted:      b.exhaustedBy != nil,
+	}
+}
@@ -0,0 +1,179 @@
+package merec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestFailFastBudget(t *testing.T) {
+	t.Parallel()
+
+	const (
+		ok   = "1"
+		fail = "qwerty"
+	)
+
+	testCases := map[string]struct {
+		givenCfg    merec.FailFastConfig
+		givenIns    []string
+		expStopAt   int
+		expFailFast merec.FailFastError
+	}{
+		"mistakes_limit": {
+			givenCfg:    merec.FailFastConfig{MistakesLimit: 3},
+			givenIns:    []string{fail, ok, fail, ok, fail},
+			expStopAt:   4,
+			expFailFast: merec.FailFastError{Failures: 3, Calls: 5},
+		},
+		"mistakes_limit_not_reached": {
+			givenCfg:  merec.FailFastConfig{MistakesLimit: 3},
+			givenIns:  []string{fail, ok, fail, ok, ok},
+			expStopAt: -1,
+		},
+		"window_size": {
+			givenCfg:    merec.FailFastConfig{MistakesLimit: 2, WindowSize: 3},
+			givenIns:    []string{fail, ok, ok, fail, ok, fail},
+			expStopAt:   5,
+			expFailFast: merec.FailFastError{Failures: 2, Calls: 3},
+		},
+		"window_size_slides": {
+			givenCfg:  merec.FailFastConfig{MistakesLimit: 2, WindowSize: 3},
+			givenIns:  []string{fail, ok, ok, fail, ok, ok, fail},
+			expStopAt: -1,
+		},
+		"failure_rate": {
+			givenCfg:    merec.FailFastConfig{FailureRate: 0.4, MinCalls: 4, WindowSize: 4},
+			givenIns:    []string{fail, fail, ok, ok, fail},
+			expStopAt:   4,
+			expFailFast: merec.FailFastError{Failures: 2, Calls: 4},
+		},
+		"failure_rate_min_calls": {
+			givenCfg:  merec.FailFastConfig{FailureRate: 0.5, MinCalls: 4},
+			givenIns:  []string{fail, fail, fail},
+			expStopAt: -1,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			budget := merec.NewFailFastBudget(tc.givenCfg)
+			call := merec.NewFailFastBudgetOption[string, int](budget).WithOption(stabCall(0))
+
+			for i, in := range tc.givenIns {
+				_, err := call(context.Background(), in)
+
+				if i != tc.expStopAt {
+					require.NotErrorIs(t, err, merec.ErrMustStop, "input %d", i)
+					continue
+				}
+
+				var ffErr *merec.FailFastError
+				require.ErrorAs(t, err, &ffErr)
+				require.ErrorIs(t, err, merec.ErrMustStop)
+				require.Error(t, ffErr.Err)
+				require.Equal(t, tc.expFailFast.Failures, ffErr.Failures)
+				require.Equal(t, tc.expFailFast.Calls, ffErr.Calls)
+				require.True(t, budget.Stats().Exhausted)
+
+				return
+			}
+
+			require.False(t, budget.Stats().Exhausted)
+		})
+	}
+}
+
+func TestFailFastBudget_WindowPeriod(t *testing.T) {
+	t.Parallel()
+
+	budget := merec.NewFailFastBudget(merec.FailFastConfig{MistakesLimit: 2, WindowPeriod: 20 * time.Millisecond})
+	call := merec.NewFailFastBudgetOption[string, int](budget).WithOption(stabCall(0))
+
+	_, err := call(context.Background(), "qwerty")
+	require.NotErrorIs(t, err, merec.ErrMustStop)
+
+	time.Sleep(40 * time.Millisecond)
+	require.Equal(t, merec.FailFastStats{Calls: 1, Failures: 1}, budget.Stats())
+
+	_, err = call(context.Background(), "qwerty")
+	require.NotErrorIs(t, err, merec.ErrMustStop)
+
+	_, err = call(context.Background(), "qwerty")
+	require.ErrorIs(t, err, merec.ErrMustStop)
+}
+
+func TestFailFastBudget_RejectsWhenExhausted(t *testing.T) {
+	t.Parallel()
+
+	call, calls := flakyCall(1, 0)
+	budget := merec.NewFailFastBudget(merec.FailFastConfig{MistakesLimit: 1})
+	call = merec.NewFailFastBudgetOption[string, int](budget).WithOption(call)
+
+	_, err := call(context.Background(), "1")
+	require.ErrorIs(t, err, errFlaky)
+	require.ErrorIs(t, err, merec.ErrMustStop)
+
+	_, err = call(context.Background(), "1")
+	require.ErrorIs(t, err, merec.ErrMustStop)
+	require.NotErrorIs(t, err, errFlaky)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestFailFastBudget_SharedByPool(t *testing.T) {
+	t.Parallel()
+
+	const (
+		mistakesLimit = 3
+		inputs        = 100
+	)
+
+	inCh := make(chan string, inputs)
+	for i := 0; i < inputs; i++ {
+		inCh <- "qwerty"
+	}
+	close(inCh)
+
+	budget := merec.NewFailFastBudget(merec.FailFastConfig{MistakesLimit: mistakesLimit})
+	option := merec.NewFailFastBudgetOption[string, int](budget)
+
+	resCh, err := merec.RunWorkerPool(context.Background(), inCh, stabCall(time.Millisecond), 5, 0, option)
+	require.NoError(t, err)
+
+	var results, stopped int
+
+	for r := range resCh {
+		results++
+
+		var ffErr *merec.FailFastError
+		if errors.As(r.Err(), &ffErr) {
+			require.Equal(t, mistakesLimit, ffErr.Failures)
+			stopped++
+		}
+	}
+
+	require.Positive(t, stopped)
+	require.Less(t, results, inputs)
+	require.True(t, budget.Stats().Exhausted)
+	require.GreaterOrEqual(t, budget.Stats().Failures, mistakesLimit)
+}
+
+func TestFailFastOption_ZeroMistakesLimit(t *testing.T) {
+	t.Parallel()
+
+	resCh, err := merec.RunFromInput(context.Background(), "qwerty", stabCall(0),
+		merec.NewFailFastOptionOption[string, int](0))
+	require.NoError(t, err)
+
+	res := <-resCh
+	require.ErrorIs(t, res.Err(), merec.ErrMustStop)
+}
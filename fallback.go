@@ -0,0 +1,46 @@
+package merec
+
+import (
+	"context"
+	"errors"
+)
+
+type fallbackOption[In, Out any] struct {
+	fallback  Call[In, Out]
+	qualifies func(error) bool
+}
+
+// NewFallbackOption is a constructor for the fallbackOption.
+// When the call fails with the error accepted by the qualifies, the fallback is called with the same input.
+// If the qualifies is nil, all the errors except the ErrMustStop are accepted.
+// When the fallback fails too, the FallbackError matching both errors is returned.
+func NewFallbackOption[In, Out any](fallback Call[In, Out], qualifies func(error) bool) CallOption[In, Out] {
+	if qualifies == nil {
+		qualifies = func(err error) bool { return !errors.Is(err, ErrMustStop) }
+	}
+
+	return fallbackOption[In, Out]{fallback: fallback, qualifies: qualifies}
+}
+
+// NewFallbackValueOption is a constructor for the fallbackOption returning the static value,
+// when the call fails with the error accepted by the qualifies, like the NewFallbackOption.
+func NewFallbackValueOption[In, Out any](value Out, qualifies func(error) bool) CallOption[In, Out] {
+	return NewFallbackOption(func(context.Context, In) (Out, error) { return value, nil }, qualifies)
+}
+
+// WithOption implements the CallOption interface for the fallbackOption.
+func (fo fallbackOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		out, err := next(ctx, in)
+		if err == nil || !fo.qualifies(err) {
+			return out, err
+		}
+
+		out, fallbackErr := fo.fallback(ctx, in)
+		if fallbackErr != nil {
+			return *new(Out), &FallbackError{Primary: err, Fallback: fallbackErr}
+		}
+
+		return out, nil
+	}
+}
@@ -0,0 +1,75 @@
+package merec_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestFallbackOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	errFallback := errors.New("fallback failed")
+	stale := func(context.Context, string) (int, error) { return -1, nil }
+	broken := func(context.Context, string) (int, error) { return 0, errFallback }
+	onlyFlaky := func(err error) bool { return errors.Is(err, errFlaky) }
+
+	testCases := map[string]struct {
+		givenFailures int32
+		givenOption   merec.CallOption[string, int]
+		expValue      int
+		expErrs       []error
+	}{
+		"primary_success": {
+			givenOption: merec.NewFallbackOption(stale, nil),
+			expValue:    1,
+		},
+		"fallback_call": {
+			givenFailures: 1,
+			givenOption:   merec.NewFallbackOption(stale, onlyFlaky),
+			expValue:      -1,
+		},
+		"fallback_value": {
+			givenFailures: 1,
+			givenOption:   merec.NewFallbackValueOption[string](42, nil),
+			expValue:      42,
+		},
+		"not_qualified": {
+			givenFailures: 1,
+			givenOption:   merec.NewFallbackOption(stale, func(error) bool { return false }),
+			expErrs:       []error{errFlaky},
+		},
+		"fallback_fails": {
+			givenFailures: 1,
+			givenOption:   merec.NewFallbackOption(broken, nil),
+			expErrs:       []error{errFlaky, errFallback},
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			call, _ := flakyCall(tc.givenFailures, 0)
+
+			out, err := tc.givenOption.WithOption(call)(ctx, "1")
+			require.Equal(t, tc.expValue, out)
+
+			if len(tc.expErrs) == 0 {
+				require.NoError(t, err)
+			}
+
+			for _, expErr := range tc.expErrs {
+				require.ErrorIs(t, err, expErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package merec
+
+import (
+	"context"
+	"errors"
+)
+
+// Future is the handle of the Result that becomes available later.
+// It is safe to share the Future between goroutines.
+type Future[Out any] struct {
+	state *futureState[Out]
+}
+
+type futureState[Out any] struct {
+	done chan struct{}
+	res  Result[Out]
+}
+
+// newFuture creates the pending Future and the function to resolve it. The function must be called once.
+func newFuture[Out any]() (Future[Out], func(Result[Out])) {
+	state := &futureState[Out]{done: make(chan struct{})}
+
+	resolve := func(res Result[Out]) {
+		state.res = res
+		close(state.done)
+	}
+
+	return Future[Out]{state: state}, resolve
+}
+
+// Done returns the channel that is closed when the Result is ready.
+func (f Future[Out]) Done() <-chan struct{} {
+	return f.state.done
+}
+
+// Result returns the Result and true if it is ready, otherwise the empty Result and false.
+func (f Future[Out]) Result() (Result[Out], bool) {
+	select {
+	case <-f.state.done:
+		return f.state.res, true
+	default:
+		return Result[Out]{}, false
+	}
+}
+
+// Await waits for the Result until the context is done, and returns its value and error.
+func (f Future[Out]) Await(ctx context.Context) (Out, error) {
+	select {
+	case <-f.state.done:
+		return f.state.res.value, f.state.res.err
+	case <-ctx.Done():
+		return *new(Out), CheckContext(ctx)
+	}
+}
+
+// AwaitAll waits for all the futures and returns their values in the same order.
+// It returns early with the error of the first failed future, or with the context error.
+func AwaitAll[Out any](ctx context.Context, futures ...Future[Out]) ([]Out, error) {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	doneCh := completions(futures, stop)
+	values := make([]Out, len(futures))
+
+	for range futures {
+		select {
+		case <-ctx.Done():
+			return nil, CheckContext(ctx)
+		case i := <-doneCh:
+			res := futures[i].state.res
+			if res.err != nil {
+				return nil, res.err
+			}
+
+			values[i] = res.value
+		}
+	}
+
+	return values, nil
+}
+
+// AwaitAny waits for the first completed future and returns its index and Result.
+// The error is returned only if there are no futures, or the context is done.
+func AwaitAny[Out any](ctx context.Context, futures ...Future[Out]) (int, Result[Out], error) {
+	if len(futures) == 0 {
+		return -1, Result[Out]{}, ErrNoFutures
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	select {
+	case <-ctx.Done():
+		return -1, Result[Out]{}, CheckContext(ctx)
+	case i := <-completions(futures, stop):
+		return i, futures[i].state.res, nil
+	}
+}
+
+// AwaitFirstSuccess waits for the first successful future and returns its value.
+// If all the futures fail, the joined errors of all of them are returned.
+func AwaitFirstSuccess[Out any](ctx context.Context, futures ...Future[Out]) (Out, error) {
+	if len(futures) == 0 {
+		return *new(Out), ErrNoFutures
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	doneCh := completions(futures, stop)
+	errs := make([]error, len(futures))
+
+	for range futures {
+		select {
+		case <-ctx.Done():
+			return *new(Out), CheckContext(ctx)
+		case i := <-doneCh:
+			res := futures[i].state.res
+			if res.err == nil {
+				return res.value, nil
+			}
+
+			errs[i] = res.err
+		}
+	}
+
+	return *new(Out), errors.Join(errs...)
+}
+
+// Then creates the Future of the call executed with the value of the successful future.
+// The error of the future is passed through untouched, the call error is wrapped like in the runners.
+// If the context is done before the future, the new Future is resolved with the context error.
+func Then[In, Out any](ctx context.Context, future Future[In], call Call[In, Out]) Future[Out] {
+	next, resolve := newFuture[Out]()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			resolve(ErrorResult[Out](CheckContext(ctx)))
+		case <-future.state.done:
+			res := future.state.res
+			if res.err != nil {
+				resolve(ErrorResult[Out](res.err))
+				return
+			}
+
+			out, _ := execute(ctx, recoverPanic(call), res.value)
+			resolve(out)
+		}
+	}()
+
+	return next
+}
+
+// Map creates the Future of the value of the successful future converted with the function.
+// The error of the future is passed through untouched, the panic of the function is wrapped like in the runners.
+// If the context is done before the future, the new Future is resolved with the context error.
+func Map[In, Out any](ctx context.Context, future Future[In], fn func(In) Out) Future[Out] {
+	return Then(ctx, future, func(_ context.Context, in In) (Out, error) {
+		return fn(in), nil
+	})
+}
+
+// completions sends the indexes of the futures into the returned channel as soon as they are completed.
+// Closing the stop channel releases the goroutines waiting for the rest of the futures.
+func completions[Out any](futures []Future[Out], stop <-chan struct{}) <-chan int {
+	doneCh := make(chan int, len(futures))
+
+	for i, f := range futures {
+		go func() {
+			select {
+			case <-f.state.done:
+				doneCh <- i
+			case <-stop:
+			}
+		}()
+	}
+
+	return doneCh
+}
@@ -0,0 +1,256 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func runFutures(t *testing.T, ctx context.Context, ins map[string]time.Duration, order ...string) []merec.Future[int] {
+	t.Helper()
+
+	futures := make([]merec.Future[int], len(order))
+
+	for i, in := range order {
+		future, err := merec.RunFuture(ctx, in, stabCall(ins[in]))
+		require.NoError(t, err)
+
+		futures[i] = future
+	}
+
+	return futures
+}
+
+func TestRunFuture(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	future, err := merec.RunFuture(ctx, "1", stabCall(10*time.Millisecond))
+	require.NoError(t, err)
+
+	_, ok := future.Result()
+	require.False(t, ok)
+
+	value, err := future.Await(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	<-future.Done()
+
+	res, ok := future.Result()
+	require.True(t, ok)
+	require.Equal(t, merec.ValueResult(1), res)
+
+	future, err = merec.RunFuture(ctx, "qwerty", stabCall(0))
+	require.NoError(t, err)
+
+	_, err = future.Await(ctx)
+	require.ErrorIs(t, err, merec.ErrBusinessLogic)
+
+	_, err = merec.RunFuture(ctx, "1", merec.Call[string, int](nil))
+	require.ErrorIs(t, err, merec.ErrNilCallFunc)
+}
+
+func TestFuture_AwaitContextDone(t *testing.T) {
+	t.Parallel()
+
+	future, err := merec.RunFuture(context.Background(), "1", stabCall(time.Hour))
+	require.NoError(t, err)
+
+	ctx, ctxCsl := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer ctxCsl()
+
+	_, err = future.Await(ctx)
+	require.ErrorIs(t, err, merec.ErrCtxDeadline)
+}
+
+func TestAwaitAll(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenIns   map[string]time.Duration
+		givenOrder []string
+		givenCtx   func() (context.Context, context.CancelFunc)
+		expValues  []int
+		expErr     error
+	}{
+		"all_success": {
+			givenIns:   map[string]time.Duration{"1": 20 * time.Millisecond, "2": time.Millisecond, "3": 0},
+			givenOrder: []string{"1", "2", "3"},
+			expValues:  []int{1, 2, 3},
+		},
+		"fails_early": {
+			givenIns:   map[string]time.Duration{"1": time.Hour, "2": time.Hour, "qwerty": 0},
+			givenOrder: []string{"1", "2", "qwerty"},
+			expErr:     merec.ErrBusinessLogic,
+		},
+		"context_done": {
+			givenIns:   map[string]time.Duration{"1": time.Hour, "2": 0, "3": 0},
+			givenOrder: []string{"1", "2", "3"},
+			givenCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(ctx, 10*time.Millisecond)
+			},
+			expErr: merec.ErrCtxDeadline,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			runCtx, runCtxCsl := context.WithCancel(ctx)
+			defer runCtxCsl()
+
+			futures := runFutures(t, runCtx, tc.givenIns, tc.givenOrder...)
+
+			awaitCtx, awaitCtxCsl := ctx, context.CancelFunc(func() {})
+			if tc.givenCtx != nil {
+				awaitCtx, awaitCtxCsl = tc.givenCtx()
+			}
+			defer awaitCtxCsl()
+
+			values, err := merec.AwaitAll(awaitCtx, futures...)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Equal(t, tc.expValues, values)
+		})
+	}
+}
+
+func TestAwaitAny(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	defer ctxCsl()
+
+	futures := runFutures(t, ctx, map[string]time.Duration{"1": time.Hour, "qwerty": time.Millisecond}, "1", "qwerty")
+
+	i, res, err := merec.AwaitAny(ctx, futures...)
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+	require.ErrorIs(t, res.Err(), merec.ErrBusinessLogic)
+
+	_, _, err = merec.AwaitAny[int](ctx)
+	require.ErrorIs(t, err, merec.ErrNoFutures)
+}
+
+func TestAwaitFirstSuccess(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	defer ctxCsl()
+
+	futures := runFutures(t, ctx,
+		map[string]time.Duration{"1": time.Hour, "2": 10 * time.Millisecond, "qwerty": 0},
+		"1", "2", "qwerty",
+	)
+
+	value, err := merec.AwaitFirstSuccess(ctx, futures...)
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+
+	futures = runFutures(t, ctx, map[string]time.Duration{"qwerty": 0, "asdf": time.Millisecond}, "qwerty", "asdf")
+
+	_, err = merec.AwaitFirstSuccess(ctx, futures...)
+	require.ErrorIs(t, err, merec.ErrBusinessLogic)
+	require.ErrorContains(t, err, "qwerty")
+	require.ErrorContains(t, err, "asdf")
+
+	_, err = merec.AwaitFirstSuccess[int](ctx)
+	require.ErrorIs(t, err, merec.ErrNoFutures)
+}
+
+func TestThenMap(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	double := func(_ context.Context, v int) (string, error) { return strconv.Itoa(v * 2), nil }
+	length := func(s string) int { return len(s) }
+
+	testCases := map[string]struct {
+		givenIn  string
+		expValue int
+		expErr   error
+	}{
+		"success": {
+			givenIn:  "60",
+			expValue: 3,
+		},
+		"error_passes_through": {
+			givenIn: "qwerty",
+			expErr:  merec.ErrBusinessLogic,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			future, err := merec.RunFuture(ctx, tc.givenIn, stabCall(time.Millisecond))
+			require.NoError(t, err)
+
+			value, err := merec.Map(ctx, merec.Then(ctx, future, double), length).Await(ctx)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Equal(t, tc.expValue, value)
+		})
+	}
+}
+
+func TestThen_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	future, err := merec.RunFuture(context.Background(), "1", stabCall(time.Hour))
+	require.NoError(t, err)
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	next := merec.Then(ctx, future, stabIntCall)
+
+	ctxCsl()
+
+	_, err = next.Await(context.Background())
+	require.ErrorIs(t, err, merec.ErrCtxCancel)
+}
+
+func TestMap_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	future, err := merec.RunFuture(context.Background(), "1", stabCall(time.Hour))
+	require.NoError(t, err)
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	next := merec.Map(ctx, future, strconv.Itoa)
+
+	ctxCsl()
+
+	_, err = next.Await(context.Background())
+	require.ErrorIs(t, err, merec.ErrCtxCancel)
+}
+
+func TestMap_Panic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	future, err := merec.RunFuture(ctx, "1", stabCall(0))
+	require.NoError(t, err)
+
+	next := merec.Map(ctx, future, func(int) int { panic("boom") })
+
+	_, err = next.Await(ctx)
+	require.ErrorIs(t, err, merec.ErrPanic)
+}
+
+func stabIntCall(_ context.Context, v int) (int, error) {
+	return v, nil
+}
@@ -0,0 +1,177 @@
+package merec
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HedgeDelay picks the time to wait for the call before starting the hedged attempt.
+type HedgeDelay interface {
+	// Delay returns the time to wait for the running attempts before starting the next one.
+	Delay() time.Duration
+	// Observe registers the latency of the successful attempt.
+	Observe(latency time.Duration)
+}
+
+type constantHedgeDelay struct {
+	delay time.Duration
+}
+
+// NewConstantHedgeDelay is a constructor for the constantHedgeDelay.
+// It always waits for the same delay before the next attempt.
+func NewConstantHedgeDelay(delay time.Duration) HedgeDelay {
+	return constantHedgeDelay{delay: delay}
+}
+
+// Delay implements the HedgeDelay interface for the constantHedgeDelay.
+func (chd constantHedgeDelay) Delay() time.Duration {
+	return chd.delay
+}
+
+// Observe implements the HedgeDelay interface for the constantHedgeDelay. It ignores the latency.
+func (constantHedgeDelay) Observe(time.Duration) {}
+
+// PercentileHedgeDelay waits for the percentile of the latest successful attempts latency, like the p95.
+// It is safe for concurrent use, so the same delay can be shared by several options.
+type PercentileHedgeDelay struct {
+	percentile float64
+	fallback   time.Duration
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+	filled    bool
+}
+
+// NewPercentileHedgeDelay is a constructor for the PercentileHedgeDelay.
+// The percentile is in the (0, 1] range, and the window is the number of the latest latencies it is calculated on.
+// Until the first latency is observed, the fallback delay is used.
+func NewPercentileHedgeDelay(percentile float64, window int, fallback time.Duration) *PercentileHedgeDelay {
+	return &PercentileHedgeDelay{
+		percentile: min(max(percentile, 0), 1),
+		fallback:   fallback,
+		latencies:  make([]time.Duration, max(window, 1)),
+	}
+}
+
+// Delay implements the HedgeDelay interface for the PercentileHedgeDelay.
+func (phd *PercentileHedgeDelay) Delay() time.Duration {
+	phd.mu.Lock()
+
+	size := phd.next
+	if phd.filled {
+		size = len(phd.latencies)
+	}
+
+	sorted := slices.Clone(phd.latencies[:size])
+
+	phd.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return phd.fallback
+	}
+
+	slices.Sort(sorted)
+
+	i := int(phd.percentile*float64(len(sorted))+0.5) - 1
+
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// Observe implements the HedgeDelay interface for the PercentileHedgeDelay.
+func (phd *PercentileHedgeDelay) Observe(latency time.Duration) {
+	phd.mu.Lock()
+	defer phd.mu.Unlock()
+
+	phd.latencies[phd.next] = latency
+	phd.next++
+
+	if phd.next == len(phd.latencies) {
+		phd.next, phd.filled = 0, true
+	}
+}
+
+type hedgeOption[In, Out any] struct {
+	delay       HedgeDelay
+	maxAttempts int
+}
+
+// NewHedgeOption is a constructor for the hedgeOption.
+// While the call is not finished within the delay, it starts one more attempt with the same input,
+// up to maxAttempts in total. The first success is returned, and the rest of the attempts are canceled
+// through their contexts. It doesn't retry the failures: when all the started attempts fail, the last error
+// is returned right away, so it can be combined with the NewRetryOption.
+func NewHedgeOption[In, Out any](delay HedgeDelay, maxAttempts int) CallOption[In, Out] {
+	return hedgeOption[In, Out]{delay: delay, maxAttempts: max(maxAttempts, 1)}
+}
+
+type hedgeAttempt[Out any] struct {
+	out Out
+	err error
+}
+
+// WithOption implements the CallOption interface for the hedgeOption.
+func (ho hedgeOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		// The attempts run concurrently, so none of them fills the metadata of the execution in.
+		attemptsCtx, attemptsCtxCsl := context.WithCancel(withoutMetaSlot(ctx))
+		defer attemptsCtxCsl()
+
+		// The buffer lets the canceled attempts finish without the reader.
+		doneCh := make(chan hedgeAttempt[Out], ho.maxAttempts)
+
+		start := func() {
+			go func() {
+				begin := time.Now()
+
+				out, err := next(attemptsCtx, in)
+				if err == nil {
+					ho.delay.Observe(time.Since(begin))
+				}
+
+				doneCh <- hedgeAttempt[Out]{out: out, err: err}
+			}()
+		}
+
+		start()
+
+		started, running := 1, 1
+
+		timer := time.NewTimer(ho.delay.Delay())
+		defer timer.Stop()
+
+		var hedgeCh <-chan time.Time
+		if started < ho.maxAttempts {
+			hedgeCh = timer.C
+		}
+
+		for {
+			select {
+			case <-hedgeCh:
+				start()
+				started++
+				running++
+
+				if started < ho.maxAttempts {
+					timer.Reset(ho.delay.Delay())
+				} else {
+					hedgeCh = nil
+				}
+
+			case attempt := <-doneCh:
+				running--
+
+				if attempt.err == nil {
+					return attempt.out, nil
+				}
+
+				if running == 0 || errors.Is(attempt.err, ErrMustStop) {
+					return *new(Out), attempt.err
+				}
+			}
+		}
+	}
+}
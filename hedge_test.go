@@ -0,0 +1,107 @@
+package merec_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestHedgeOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenLatencies []time.Duration
+		givenFailures  int32
+		givenAttempts  int
+		expRes         merec.Result[int]
+		expCalls       int32
+		expCanceled    int32
+	}{
+		"fast_first_attempt": {
+			givenLatencies: []time.Duration{0},
+			givenAttempts:  3,
+			expRes:         merec.ValueResult(1),
+			expCalls:       1,
+		},
+		"hedged_attempt_wins": {
+			givenLatencies: []time.Duration{time.Hour, time.Millisecond},
+			givenAttempts:  3,
+			expRes:         merec.ValueResult(1),
+			expCalls:       2,
+			expCanceled:    1,
+		},
+		"attempts_limited": {
+			givenLatencies: []time.Duration{50 * time.Millisecond, time.Hour, time.Hour},
+			givenAttempts:  2,
+			expRes:         merec.ValueResult(1),
+			expCalls:       2,
+			expCanceled:    1,
+		},
+		"all_attempts_fail": {
+			givenLatencies: []time.Duration{0},
+			givenFailures:  1,
+			givenAttempts:  3,
+			expRes:         merec.ErrorResult[int](errFlaky),
+			expCalls:       1,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			var calls, canceled atomic.Int32
+
+			call := func(ctx context.Context, in string) (int, error) {
+				n := calls.Add(1)
+				if n <= tc.givenFailures {
+					return 0, errFlaky
+				}
+
+				out, err := stabCall(tc.givenLatencies[n-1])(ctx, in)
+				if err != nil {
+					canceled.Add(1)
+				}
+
+				return out, err
+			}
+
+			option := merec.NewHedgeOption[string, int](merec.NewConstantHedgeDelay(10*time.Millisecond), tc.givenAttempts)
+
+			out, err := option.WithOption(call)(ctx, "1")
+			require.ErrorIs(t, err, tc.expRes.Err())
+			require.Equal(t, tc.expRes.Value(), out)
+
+			require.Eventually(t, func() bool { return canceled.Load() == tc.expCanceled }, time.Second, time.Millisecond)
+			require.Equal(t, tc.expCalls, calls.Load())
+		})
+	}
+}
+
+func TestPercentileHedgeDelay(t *testing.T) {
+	t.Parallel()
+
+	delay := merec.NewPercentileHedgeDelay(0.9, 10, time.Second)
+	require.Equal(t, time.Second, delay.Delay())
+
+	for i := 1; i <= 10; i++ {
+		delay.Observe(time.Duration(i) * time.Millisecond)
+	}
+
+	require.Equal(t, 9*time.Millisecond, delay.Delay())
+
+	for i := 0; i < 10; i++ {
+		delay.Observe(time.Millisecond)
+	}
+
+	require.Equal(t, time.Millisecond, delay.Delay())
+}
@@ -0,0 +1,153 @@
+package merec
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ResultMeta describes how the Result was produced. It is collected only for the calls wrapped with
+// the NewMetadataOption.
+type ResultMeta struct {
+	// Input is the input the call was executed with.
+	Input any
+	// CorrelationID is the ID of the input returned by the function passed to the option.
+	CorrelationID string
+	// Seq is the number of the execution within the runner, starting from 0, in the order the executions started.
+	Seq uint64
+	// WorkerID is the index of the worker that executed the call, starting from 0.
+	WorkerID int
+	// Start is the time the first attempt started.
+	Start time.Time
+	// End is the time the last attempt finished.
+	End time.Time
+	// Attempts is the number of the call executions, it is increased by the NewRetryOption.
+	Attempts int
+}
+
+// Duration returns the time spent on the execution, including all the attempts.
+func (m ResultMeta) Duration() time.Duration {
+	return m.End.Sub(m.Start)
+}
+
+type metaSlotKey struct{}
+
+// metaSlot is passed through the context of the call, so the options can fill the metadata in.
+// The options may run the call in several goroutines, so the metadata is guarded by the mutex,
+// and it is not changed anymore once it is finished.
+type metaSlot struct {
+	mu       sync.Mutex
+	meta     *ResultMeta
+	finished bool
+}
+
+type workerInfoKey struct{}
+
+// workerInfo is passed through the context of the worker, so the metadata can refer to the worker.
+type workerInfo struct {
+	seq *atomic.Uint64
+	id  int
+}
+
+// withWorker returns the context of the worker number id, the seq is the execution counter shared by the runner.
+func withWorker(ctx context.Context, seq *atomic.Uint64, id int) context.Context {
+	return context.WithValue(ctx, workerInfoKey{}, workerInfo{seq: seq, id: id})
+}
+
+// withMetaSlot returns the context with the empty metadata slot.
+func withMetaSlot(ctx context.Context) (context.Context, *metaSlot) {
+	slot := &metaSlot{}
+
+	return context.WithValue(ctx, metaSlotKey{}, slot), slot
+}
+
+// withoutMetaSlot returns the context without the metadata slot. The options pass it to the calls
+// that may outlive the execution or run concurrently with the other attempts.
+func withoutMetaSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, metaSlotKey{}, (*metaSlot)(nil))
+}
+
+// metaSlotFrom returns the metadata slot of the context, or nil if there is none.
+func metaSlotFrom(ctx context.Context) *metaSlot {
+	slot, _ := ctx.Value(metaSlotKey{}).(*metaSlot)
+
+	return slot
+}
+
+// finishMeta completes the metadata collected in the slot, and returns nil if it was not collected.
+func finishMeta(slot *metaSlot) *ResultMeta {
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	slot.finished = true
+
+	if slot.meta == nil {
+		return nil
+	}
+
+	meta := *slot.meta
+	meta.End = time.Now()
+
+	return &meta
+}
+
+// recordAttempt sets the number of the call executions if the metadata is collected.
+func recordAttempt(ctx context.Context, attempt int) {
+	slot := metaSlotFrom(ctx)
+	if slot == nil {
+		return
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	if slot.meta != nil && !slot.finished {
+		slot.meta.Attempts = attempt
+	}
+}
+
+type metadataOption[In, Out any] struct {
+	correlationID func(In) string
+}
+
+// NewMetadataOption is a constructor for the metadataOption.
+// It enables the collection of the ResultMeta, available with the Result.Meta.
+// The correlationID returns the ID of the input, it is optional.
+func NewMetadataOption[In, Out any](correlationID func(In) string) CallOption[In, Out] {
+	return metadataOption[In, Out]{correlationID: correlationID}
+}
+
+// WithOption implements the CallOption interface for the metadataOption.
+// The metadata is started by the first execution. The hedge and singleflight options run the call without it,
+// so the option must be placed after them to cover their executions.
+func (mo metadataOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		if slot := metaSlotFrom(ctx); slot != nil {
+			mo.start(ctx, slot, in)
+		}
+
+		return next(ctx, in)
+	}
+}
+
+// start fills the metadata in, unless it is already started or finished.
+func (mo metadataOption[In, Out]) start(ctx context.Context, slot *metaSlot, in In) {
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	if slot.meta != nil || slot.finished {
+		return
+	}
+
+	slot.meta = &ResultMeta{Input: in, Start: time.Now(), Attempts: 1}
+
+	if info, ok := ctx.Value(workerInfoKey{}).(workerInfo); ok {
+		slot.meta.WorkerID = info.id
+		slot.meta.Seq = info.seq.Add(1) - 1
+	}
+
+	if mo.correlationID != nil {
+		slot.meta.CorrelationID = mo.correlationID(in)
+	}
+}
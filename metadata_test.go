@@ -0,0 +1,121 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestMetadataOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	const poolSize = 2
+
+	call, _ := flakyCall(1, time.Millisecond)
+
+	resCh, err := merec.RunWorkerPool(ctx, givenCh(0), call, poolSize, 0,
+		merec.NewMetadataOption[string, int](func(in string) string { return "id-" + in }),
+		merec.NewRetryOption[string, int](merec.NewConstantBackoff(0), 2, 0),
+	)
+	require.NoError(t, err)
+
+	seqs := make([]uint64, 0, workLoad)
+	attempts := 0
+
+	for res := range resCh {
+		require.NoError(t, res.Err())
+
+		meta, ok := res.Meta()
+		require.True(t, ok)
+		require.Equal(t, res.Value(), mustAtoi(t, meta.Input.(string)))
+		require.Equal(t, "id-"+meta.Input.(string), meta.CorrelationID)
+		require.Less(t, meta.WorkerID, poolSize)
+		require.GreaterOrEqual(t, meta.Duration(), time.Millisecond)
+
+		seqs = append(seqs, meta.Seq)
+		attempts += meta.Attempts
+	}
+
+	require.ElementsMatch(t, []uint64{0, 1, 2, 3, 4}, seqs)
+	require.Equal(t, workLoad+1, attempts)
+}
+
+func TestMetadataOption_Disabled(t *testing.T) {
+	t.Parallel()
+
+	resCh, err := merec.RunFromChan(context.Background(), givenCh(0), stabCall(0))
+	require.NoError(t, err)
+
+	for res := range resCh {
+		_, ok := res.Meta()
+		require.False(t, ok)
+	}
+}
+
+// TestMetadataOption_ConcurrentAttempts must be run with the -race flag to detect the shared metadata.
+func TestMetadataOption_ConcurrentAttempts(t *testing.T) {
+	t.Parallel()
+
+	metadata := merec.NewMetadataOption[string, int](nil)
+	hedge := merec.NewHedgeOption[string, int](merec.NewConstantHedgeDelay(0), 3)
+	singleflight := merec.NewSingleflightOption[string, int](func(in string) string { return in })
+
+	testCases := map[string]struct {
+		givenOptions []merec.CallOption[string, int]
+		expMeta      bool
+	}{
+		"metadata_inside_hedge": {
+			givenOptions: []merec.CallOption[string, int]{metadata, hedge},
+		},
+		"metadata_outside_hedge": {
+			givenOptions: []merec.CallOption[string, int]{hedge, metadata},
+			expMeta:      true,
+		},
+		"metadata_inside_singleflight": {
+			givenOptions: []merec.CallOption[string, int]{metadata, singleflight},
+		},
+		"metadata_outside_singleflight": {
+			givenOptions: []merec.CallOption[string, int]{singleflight, metadata},
+			expMeta:      true,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			resCh, err := merec.RunWorkerPool(context.Background(), givenCh(0), stabCall(time.Millisecond), 2, 0,
+				tc.givenOptions...)
+			require.NoError(t, err)
+
+			for res := range resCh {
+				require.NoError(t, res.Err())
+
+				meta, ok := res.Meta()
+				require.Equal(t, tc.expMeta, ok)
+
+				if ok {
+					require.Equal(t, 1, meta.Attempts)
+				}
+			}
+		})
+	}
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+
+	v, err := strconv.Atoi(s)
+	require.NoError(t, err)
+
+	return v
+}
@@ -17,6 +17,7 @@ type Call[In, Out any] func(context.Context, In) (Out, error)
 type Result[Out any] struct {
 	value Out
 	err   error
+	meta  *ResultMeta
 }
 
 // String implements io.Stringer interface.
@@ -34,6 +35,15 @@ func (r Result[Out]) Err() error {
 	return r.err
 }
 
+// Meta returns the metadata of the execution. It is available only for the calls wrapped with the NewMetadataOption.
+func (r Result[Out]) Meta() (ResultMeta, bool) {
+	if r.meta == nil {
+		return ResultMeta{}, false
+	}
+
+	return *r.meta, true
+}
+
 // ValueResult creates a new success result with a set value.
 func ValueResult[Out any](value Out) Result[Out] {
 	return Result[Out]{value: value}
@@ -2,6 +2,7 @@ package merec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -25,23 +26,149 @@ func (to timeoutOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
 	}
 }
 
+type retryOption[In, Out any] struct {
+	backoff        BackoffStrategy
+	maxAttempts    int
+	maxElapsedTime time.Duration
+}
+
+// NewRetryOption is a constructor for the retryOption.
+// The maxAttempts limits the total number of the call executions, and the maxElapsedTime limits the total time
+// spent on them. Non-positive values disable the corresponding limit, so with both disabled it retries until
+// the context is done. The ErrMustStop errors are never retried.
+func NewRetryOption[In, Out any](
+	backoff BackoffStrategy,
+	maxAttempts int,
+	maxElapsedTime time.Duration,
+) CallOption[In, Out] {
+	if backoff == nil {
+		backoff = NewConstantBackoff(0)
+	}
+
+	return retryOption[In, Out]{backoff: backoff, maxAttempts: maxAttempts, maxElapsedTime: maxElapsedTime}
+}
+
+// WithOption implements the CallOption interface for the retryOption.
+func (ro retryOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		start := time.Now()
+
+		var delay time.Duration
+
+		for attempt := 1; ; attempt++ {
+			out, err := next(ctx, in)
+			recordAttempt(ctx, attempt)
+
+			if err == nil {
+				return out, nil
+			}
+
+			if errors.Is(err, ErrMustStop) {
+				return *new(Out), err
+			}
+
+			if ro.maxAttempts > 0 && attempt >= ro.maxAttempts {
+				return *new(Out), err
+			}
+
+			delay = ro.backoff.Delay(attempt, delay)
+			if ro.maxElapsedTime > 0 && time.Since(start)+delay > ro.maxElapsedTime {
+				return *new(Out), err
+			}
+
+			if ctxErr := sleepContext(ctx, delay); ctxErr != nil {
+				return *new(Out), fmt.Errorf("%w: %w", ctxErr, err)
+			}
+		}
+	}
+}
+
 type failFastOption[In, Out any] struct {
-	mistakesLimit int
+	budget *FailFastBudget
 }
 
 // NewFailFastOptionOption is a constructor for the failFastOption.
+// It interrupts the processing after mistakesLimit failures shared by all the calls wrapped with the option.
+// The mistakesLimit below 1 interrupts it on the first failure.
 func NewFailFastOptionOption[In, Out any](mistakesLimit int) CallOption[In, Out] {
-	return failFastOption[In, Out]{mistakesLimit: mistakesLimit}
+	return NewFailFastBudgetOption[In, Out](NewFailFastBudget(FailFastConfig{MistakesLimit: max(mistakesLimit, 1)}))
+}
+
+// NewFailFastBudgetOption is a constructor for the failFastOption with the custom budget.
+// The same budget can be passed to several options to share it, and to inspect its state.
+func NewFailFastBudgetOption[In, Out any](budget *FailFastBudget) CallOption[In, Out] {
+	return failFastOption[In, Out]{budget: budget}
 }
 
 // WithOption implements the CallOption interface for the failFastOption.
-func (failFastOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+// Once the budget is exhausted, the calls are rejected with the ErrMustStop without the execution.
+func (ffo failFastOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
 	return func(ctx context.Context, in In) (Out, error) {
+		if exhaustedBy := ffo.budget.exhausted(); exhaustedBy != nil {
+			return *new(Out), &FailFastError{Failures: exhaustedBy.Failures, Calls: exhaustedBy.Calls}
+		}
+
 		out, err := next(ctx, in)
+		if ffErr := ffo.budget.record(err); ffErr != nil {
+			return *new(Out), ffErr
+		}
+
 		if err != nil {
-			return *new(Out), fmt.Errorf("%w: %w", ErrMustStop, err)
+			return *new(Out), err
 		}
 
 		return out, nil
 	}
 }
+
+// withOptions wraps the call with the options, the first option is the innermost one.
+// Unless it is disabled with the NewNoPanicRecoveryOption, the panic of the call is recovered before the options,
+// so all of them see it as the PanicError, and the panics of the options themselves are recovered outside the chain.
+// The dead letter options wrap the whole chain, so they receive every failure whatever their position is.
+func withOptions[In, Out any](call Call[In, Out], options []CallOption[In, Out]) Call[In, Out] {
+	if call == nil {
+		return nil
+	}
+
+	recovery := true
+
+	var deadLetters []CallOption[In, Out]
+
+	for _, o := range options {
+		switch o.(type) {
+		case noPanicRecoveryOption[In, Out]:
+			recovery = false
+		case deadLetterOption[In, Out]:
+			deadLetters = append(deadLetters, o)
+		}
+	}
+
+	next := call
+	if recovery {
+		next = recoverPanic(next)
+	}
+
+	for _, o := range options {
+		if _, ok := o.(deadLetterOption[In, Out]); !ok {
+			next = o.WithOption(next)
+		}
+	}
+
+	if recovery {
+		next = recoverPanic(next)
+	}
+
+	if len(deadLetters) == 0 {
+		return next
+	}
+
+	for _, o := range deadLetters {
+		next = o.WithOption(next)
+	}
+
+	if recovery {
+		next = recoverPanic(next)
+	}
+
+	return next
+}
@@ -0,0 +1,112 @@
+package merec_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestRetryOption(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenFailures       int32
+		givenMaxAttempts    int
+		givenMaxElapsedTime time.Duration
+		givenBackoff        merec.BackoffStrategy
+		expRes              merec.Result[int]
+		expCalls            int32
+	}{
+		"success_first_attempt": {
+			givenMaxAttempts: 3,
+			givenBackoff:     merec.NewConstantBackoff(time.Millisecond),
+			expRes:           merec.ValueResult(1),
+			expCalls:         1,
+		},
+		"success_after_retries": {
+			givenFailures:    2,
+			givenMaxAttempts: 3,
+			givenBackoff:     merec.NewExponentialBackoff(time.Millisecond, 10*time.Millisecond),
+			expRes:           merec.ValueResult(1),
+			expCalls:         3,
+		},
+		"max_attempts_reached": {
+			givenFailures:    5,
+			givenMaxAttempts: 3,
+			givenBackoff:     merec.NewDecorrelatedJitterBackoff(time.Millisecond, 10*time.Millisecond),
+			expRes:           merec.ErrorResult[int](errFlaky),
+			expCalls:         3,
+		},
+		"max_elapsed_time_reached": {
+			givenFailures:       5,
+			givenMaxElapsedTime: 50 * time.Millisecond,
+			givenBackoff:        merec.NewConstantBackoff(time.Second),
+			expRes:              merec.ErrorResult[int](errFlaky),
+			expCalls:            1,
+		},
+		"nil_backoff": {
+			givenFailures:    1,
+			givenMaxAttempts: 2,
+			expRes:           merec.ValueResult(1),
+			expCalls:         2,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			call, calls := flakyCall(tc.givenFailures, time.Microsecond)
+			option := merec.NewRetryOption[string, int](tc.givenBackoff, tc.givenMaxAttempts, tc.givenMaxElapsedTime)
+
+			resCh, err := merec.RunFromInput(context.Background(), "1", call, option)
+			require.NoError(t, err)
+
+			res := <-resCh
+			require.ErrorIs(t, res.Err(), tc.expRes.Err())
+			require.Equal(t, tc.expRes.Value(), res.Value())
+			require.Equal(t, tc.expCalls, calls.Load())
+		})
+	}
+}
+
+func TestRetryOption_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer ctxCsl()
+
+	call, calls := flakyCall(100, time.Microsecond)
+	option := merec.NewRetryOption[string, int](merec.NewConstantBackoff(time.Hour), 0, 0)
+
+	resCh, err := merec.RunFromInput(ctx, "1", call, option)
+	require.NoError(t, err)
+
+	res := <-resCh
+	require.ErrorIs(t, res.Err(), merec.ErrCtxDeadline)
+	require.ErrorIs(t, res.Err(), errFlaky)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryOption_MustStop(t *testing.T) {
+	t.Parallel()
+
+	call, calls := flakyCall(100, time.Microsecond)
+	options := []merec.CallOption[string, int]{
+		merec.NewFailFastOptionOption[string, int](1),
+		merec.NewRetryOption[string, int](merec.NewConstantBackoff(time.Millisecond), 5, 0),
+	}
+
+	resCh, err := merec.RunFromInput(context.Background(), "1", call, options...)
+	require.NoError(t, err)
+
+	res := <-resCh
+	require.ErrorIs(t, res.Err(), merec.ErrMustStop)
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,195 @@
+package merec
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// StageConfig describes the worker pool of the Pipeline stage.
+type StageConfig struct {
+	// PoolSize is the number of goroutine workers of the stage. The minimal value is 1.
+	PoolSize int
+	// BufSize is the size of the stage output buffer.
+	BufSize int
+}
+
+// Pipeline is the chain of stages, every stage is the worker pool consuming the successful values
+// of the previous one. The failed results skip the rest of the stages and reach the output untouched.
+// The Pipeline is immutable, so the same prefix can be extended in different ways.
+type Pipeline[In, Out any] struct {
+	run func(ctx, callCtx context.Context, inCh <-chan Result[In], stop context.CancelFunc) chan Result[Out]
+	err error
+}
+
+// NewPipeline creates the Pipeline with the first stage executing the call with the inputs.
+func NewPipeline[In, Out any](call Call[In, Out], cfg StageConfig, options ...CallOption[In, Out]) Pipeline[In, Out] {
+	if err := validateStage(call, cfg); err != nil {
+		return Pipeline[In, Out]{err: err}
+	}
+
+	call = withOptions(call, options)
+
+	return Pipeline[In, Out]{
+		run: func(ctx, callCtx context.Context, inCh <-chan Result[In], stop context.CancelFunc) chan Result[Out] {
+			return runStage(ctx, callCtx, inCh, call, cfg, stop)
+		},
+	}
+}
+
+// AddStage creates the Pipeline extended with the stage executing the call with the values of the last stage.
+// The error of the stage configuration is reported by the Run.
+func AddStage[In, Mid, Out any](
+	p Pipeline[In, Mid],
+	call Call[Mid, Out],
+	cfg StageConfig,
+	options ...CallOption[Mid, Out],
+) Pipeline[In, Out] {
+	if p.err != nil {
+		return Pipeline[In, Out]{err: p.err}
+	}
+
+	if err := validateStage(call, cfg); err != nil {
+		return Pipeline[In, Out]{err: err}
+	}
+
+	call = withOptions(call, options)
+
+	return Pipeline[In, Out]{
+		run: func(ctx, callCtx context.Context, inCh <-chan Result[In], stop context.CancelFunc) chan Result[Out] {
+			return runStage(ctx, callCtx, p.run(ctx, callCtx, inCh, stop), call, cfg, stop)
+		},
+	}
+}
+
+// Run starts all the stages to process the inputs from the channel.
+// Returns the channel to be listened to, to get the Result values of the last stage and the failures of all of them.
+// The ErrMustStop of any stage stops feeding the inputs and interrupts the calls in flight of all the stages.
+// The ErrMustStop result and the failures already passed on still reach the output, the rest of the values are dropped.
+// Once the context is done, all the stages stop consuming, drop the results of the calls in flight,
+// and the output finishes with the ErrCtxCancel or ErrCtxDeadline result, waiting a little for the consumer if full.
+func (p Pipeline[In, Out]) Run(ctx context.Context, inCh <-chan In) (<-chan Result[Out], error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if p.run == nil {
+		return nil, ErrNilCallFunc
+	}
+
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	if inCh == nil {
+		return nil, ErrNilInChan
+	}
+
+	pipeCtx, pipeCtxCsl := context.WithCancel(ctx)
+
+	srcCh := make(chan Result[In])
+
+	go func() {
+		defer close(srcCh)
+
+		for {
+			select {
+			case <-pipeCtx.Done():
+				return
+			case in, ok := <-inCh:
+				if !ok || !sendContext(pipeCtx, srcCh, ValueResult(in)) {
+					return
+				}
+			}
+		}
+	}()
+
+	outCh := p.run(ctx, pipeCtx, srcCh, pipeCtxCsl)
+
+	return mergeChanPoolContext(ctx, []chan Result[Out]{outCh}, pipeCtxCsl), nil
+}
+
+// runStage starts the workers executing the call with the successful values and passing the failures through.
+// The calls get the callCtx, once it is done, the values are dropped, but the failures are still passed through
+// until the input channel is closed. The output is closed when all the workers are finished.
+func runStage[In, Out any](
+	ctx context.Context,
+	callCtx context.Context,
+	inCh <-chan Result[In],
+	call Call[In, Out],
+	cfg StageConfig,
+	stop context.CancelFunc,
+) chan Result[Out] {
+	outCh := make(chan Result[Out], cfg.BufSize)
+
+	var wg sync.WaitGroup
+
+	wg.Add(cfg.PoolSize)
+
+	var seq atomic.Uint64
+
+	worker := func(id int) {
+		defer wg.Done()
+
+		workerCtx := withWorker(callCtx, &seq, id)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case in, ok := <-inCh:
+				if !ok {
+					return
+				}
+
+				if in.err != nil {
+					if !sendContext(ctx, outCh, Result[Out]{err: in.err, meta: in.meta}) {
+						return
+					}
+
+					continue
+				}
+
+				if callCtx.Err() != nil {
+					continue
+				}
+
+				res, mustStop := execute(workerCtx, call, in.value)
+				if callCtx.Err() != nil && !mustStop {
+					continue
+				}
+
+				if !sendContext(ctx, outCh, res) {
+					return
+				}
+
+				if mustStop {
+					stop()
+				}
+			}
+		}
+	}
+
+	for i := 0; i < cfg.PoolSize; i++ {
+		go worker(i)
+	}
+
+	go func() {
+		wg.Wait()
+		close(outCh)
+	}()
+
+	return outCh
+}
+
+func validateStage[In, Out any](call Call[In, Out], cfg StageConfig) error {
+	if call == nil {
+		return ErrNilCallFunc
+	}
+
+	if cfg.PoolSize < 1 {
+		return ErrInvalidPoolSize
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package merec_test
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	double := func(_ context.Context, v int) (int, error) { return v * 2, nil }
+	format := func(_ context.Context, v int) (string, error) { return strconv.Itoa(v), nil }
+
+	pipeline := merec.AddStage(
+		merec.AddStage(
+			merec.NewPipeline(stabCall(time.Millisecond), merec.StageConfig{PoolSize: 3, BufSize: 1}),
+			double,
+			merec.StageConfig{PoolSize: 2},
+		),
+		format,
+		merec.StageConfig{PoolSize: 1},
+	)
+
+	inCh := make(chan string)
+
+	go func() {
+		defer close(inCh)
+
+		for _, in := range []string{"1", "qwerty", "2", "3"} {
+			inCh <- in
+		}
+	}()
+
+	resCh, err := pipeline.Run(ctx, inCh)
+	require.NoError(t, err)
+
+	var (
+		values []string
+		errs   []error
+	)
+
+	for res := range resCh {
+		if res.Err() != nil {
+			errs = append(errs, res.Err())
+			continue
+		}
+
+		values = append(values, res.Value())
+	}
+
+	require.ElementsMatch(t, []string{"2", "4", "6"}, values)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], merec.ErrBusinessLogic)
+	require.ErrorContains(t, errs[0], "qwerty")
+}
+
+func TestPipeline_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	identity := func(_ context.Context, v int) (int, error) { return v, nil }
+
+	testCases := map[string]struct {
+		givenPipeline merec.Pipeline[string, int]
+		expErr        error
+	}{
+		"nil_first_call": {
+			givenPipeline: merec.AddStage(
+				merec.NewPipeline[string, int](nil, merec.StageConfig{PoolSize: 1}),
+				identity,
+				merec.StageConfig{PoolSize: 1},
+			),
+			expErr: merec.ErrNilCallFunc,
+		},
+		"invalid_pool_size": {
+			givenPipeline: merec.AddStage(
+				merec.NewPipeline(stabCall(0), merec.StageConfig{PoolSize: 1}),
+				identity,
+				merec.StageConfig{},
+			),
+			expErr: merec.ErrInvalidPoolSize,
+		},
+		"empty": {
+			givenPipeline: merec.Pipeline[string, int]{},
+			expErr:        merec.ErrNilCallFunc,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.givenPipeline.Run(ctx, givenCh(0))
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestPipeline_ContextDone(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+
+	pipeline := merec.AddStage(
+		merec.NewPipeline(stabCall(0), merec.StageConfig{PoolSize: 2}),
+		func(ctx context.Context, v int) (int, error) { return stabCall(time.Hour)(ctx, strconv.Itoa(v)) },
+		merec.StageConfig{PoolSize: 2},
+	)
+
+	resCh, err := pipeline.Run(ctx, endlessCh(workLoad))
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	ctxCsl()
+
+	results := make([]merec.Result[int], 0, 1)
+
+	for res := range resCh {
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err(), merec.ErrCtxCancel)
+
+	requireNoGoroutineLeak(t, baseline)
+}
+
+func TestPipeline_MustStop(t *testing.T) {
+	t.Parallel()
+
+	stop := func(context.Context, int) (int, error) { return 0, merec.ErrMustStop }
+	identity := func(_ context.Context, v int) (int, error) { return v, nil }
+
+	testCases := map[string]struct {
+		givenPipeline merec.Pipeline[string, int]
+	}{
+		"last_stage": {
+			givenPipeline: merec.AddStage(
+				merec.NewPipeline(stabCall(0), merec.StageConfig{PoolSize: 1}),
+				stop,
+				merec.StageConfig{PoolSize: 1},
+			),
+		},
+		"middle_stage": {
+			givenPipeline: merec.AddStage(
+				merec.AddStage(
+					merec.NewPipeline(stabCall(0), merec.StageConfig{PoolSize: 1}),
+					stop,
+					merec.StageConfig{PoolSize: 1},
+				),
+				identity,
+				merec.StageConfig{PoolSize: 2},
+			),
+		},
+		"first_stage": {
+			givenPipeline: merec.AddStage(
+				merec.NewPipeline(
+					func(ctx context.Context, in string) (int, error) { return stop(ctx, 0) },
+					merec.StageConfig{PoolSize: 2},
+				),
+				identity,
+				merec.StageConfig{PoolSize: 1},
+			),
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			resCh, err := tc.givenPipeline.Run(context.Background(), endlessCh(workLoad))
+			require.NoError(t, err)
+
+			results := make([]merec.Result[int], 0, 1)
+
+			for res := range resCh {
+				results = append(results, res)
+			}
+
+			require.NotEmpty(t, results)
+
+			for _, res := range results {
+				require.ErrorIs(t, res.Err(), merec.ErrMustStop)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package merec
+
+import (
+	"context"
+	"sync"
+)
+
+// ShutdownReport is the outcome of the PoolControl.Shutdown.
+type ShutdownReport struct {
+	// Completed is the number of the calls finished on their own, successfully or not.
+	Completed int
+	// Cancelled is the number of the calls in flight canceled when the shutdown context was done.
+	Cancelled int
+	// NotStarted is the number of the inputs left in the input channel buffer. They are not consumed,
+	// so the owner of the channel can still save them.
+	NotStarted int
+}
+
+// PoolControl controls the pool started with it from the outside. It can control a single pool only.
+// The pool can be paused and resumed any number of times, and shut down once.
+type PoolControl struct {
+	// quit is closed when the pool must stop taking the new inputs.
+	quit     chan struct{}
+	quitOnce sync.Once
+	// abortCtx is canceled when the calls in flight must be interrupted.
+	abortCtx context.Context
+	abortCsl context.CancelFunc
+	// done is closed when all the workers of the pool are finished.
+	done chan struct{}
+
+	mu       sync.Mutex
+	attached bool
+	pending  func() int
+	// paused is closed while the pool is paused, and resumed is closed while it is not.
+	paused  chan struct{}
+	resumed chan struct{}
+
+	inFlight  int
+	completed int
+	cancelled int
+}
+
+// NewPoolControl is a constructor for the PoolControl.
+func NewPoolControl() *PoolControl {
+	abortCtx, abortCsl := context.WithCancel(context.Background())
+
+	resumed := make(chan struct{})
+	close(resumed)
+
+	return &PoolControl{
+		quit:     make(chan struct{}),
+		abortCtx: abortCtx,
+		abortCsl: abortCsl,
+		done:     make(chan struct{}),
+		paused:   make(chan struct{}),
+		resumed:  resumed,
+	}
+}
+
+// Pause stops the workers from taking the new inputs. The calls in flight are completed,
+// and the inputs stay in the input channel until the Resume.
+func (c *PoolControl) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if TryReedSignal(c.paused) {
+		return
+	}
+
+	close(c.paused)
+	c.resumed = make(chan struct{})
+}
+
+// Resume lets the workers take the new inputs again.
+func (c *PoolControl) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if TryReedSignal(c.resumed) {
+		return
+	}
+
+	close(c.resumed)
+	c.paused = make(chan struct{})
+}
+
+// Paused reports whether the pool is paused.
+func (c *PoolControl) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return TryReedSignal(c.paused)
+}
+
+// Shutdown stops taking the new inputs and waits for the calls in flight to complete until the context is done.
+// After that, the rest of the calls are canceled, their results are the ErrCtxCancel, and it returns right away
+// with the context error, reporting them as canceled. The calls that ignore the cancellation may still run
+// for a while, and the output must still be drained to let the workers finish. It is safe to call it several times.
+func (c *PoolControl) Shutdown(ctx context.Context) (ShutdownReport, error) {
+	if ctx == nil {
+		return ShutdownReport{}, ErrNilContext
+	}
+
+	c.quitOnce.Do(func() { close(c.quit) })
+
+	c.mu.Lock()
+	attached := c.attached
+	c.mu.Unlock()
+
+	if !attached {
+		return ShutdownReport{}, nil
+	}
+
+	var err error
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		c.abortCsl()
+
+		err = CheckContext(ctx)
+	}
+
+	return c.report(), err
+}
+
+// report returns the outcome of the calls so far. Once the calls are aborted, the ones in flight are reported
+// as canceled, since that is the outcome they get when they return.
+func (c *PoolControl) report() ShutdownReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cancelled := c.cancelled
+	if c.abortCtx.Err() != nil {
+		cancelled += c.inFlight
+	}
+
+	return ShutdownReport{
+		Completed:  c.completed,
+		Cancelled:  cancelled,
+		NotStarted: c.pending(),
+	}
+}
+
+// attach binds the control to the pool, the pending reports the number of the inputs waiting in the input channel.
+func (c *PoolControl) attach(pending func() int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.attached {
+		return ErrPoolControlInUse
+	}
+
+	c.attached, c.pending = true, pending
+
+	return nil
+}
+
+// signals returns the channels closed when the pool is paused and resumed. They are nil for the nil control.
+func (c *PoolControl) signals() (<-chan struct{}, <-chan struct{}) {
+	if c == nil {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.paused, c.resumed
+}
+
+// finish marks all the workers of the pool finished.
+func (c *PoolControl) finish() {
+	close(c.done)
+}
+
+// withControl wraps the call to count the outcomes and to cancel it on the abort.
+func withControl[In, Out any](c *PoolControl, call Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		ctx, ctxCsl := context.WithCancel(ctx)
+		defer ctxCsl()
+
+		stop := context.AfterFunc(c.abortCtx, ctxCsl)
+		defer stop()
+
+		c.mu.Lock()
+		c.inFlight++
+		c.mu.Unlock()
+
+		out, err := call(ctx, in)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.inFlight--
+
+		if c.abortCtx.Err() != nil {
+			c.cancelled++
+			return *new(Out), ErrCtxCancel
+		}
+
+		c.completed++
+
+		return out, err
+	}
+}
+
+// RunControlledWorkerPool starts the pool of goroutine workers like the RunWorkerPool, and binds it to the control.
+// While the PoolControl is paused, the workers take no new inputs from the input channel.
+// Once the PoolControl.Shutdown is called, the workers stop consuming from the input channel,
+// and the output is closed after the calls in flight are finished or canceled.
+func RunControlledWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	control *PoolControl,
+	poolSize int,
+	bufSize int,
+	options ...CallOption[In, Out],
+) (<-chan Result[Out], error) {
+	if control == nil {
+		return nil, ErrNilPoolControl
+	}
+
+	return runWorkerPool(ctx, inCh, withOptions(call, options), poolSize, bufSize, control)
+}
+
+// RunControlledFromChan starts a separate goroutine to consume from the input channel like the RunFromChan,
+// and binds it to the control.
+func RunControlledFromChan[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	control *PoolControl,
+	options ...CallOption[In, Out],
+) (<-chan Result[Out], error) {
+	if control == nil {
+		return nil, ErrNilPoolControl
+	}
+
+	return runFromChan(ctx, inCh, withOptions(call, options), control)
+}
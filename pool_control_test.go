@@ -0,0 +1,237 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+// blockingCall signals every started call and blocks until the release is closed or the context is done.
+func blockingCall(started chan<- struct{}, release <-chan struct{}) merec.Call[string, int] {
+	return func(ctx context.Context, in string) (int, error) {
+		started <- struct{}{}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-release:
+			return strconv.Atoi(in)
+		}
+	}
+}
+
+func TestRunControlledWorkerPool_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenRelease bool
+		expReport    merec.ShutdownReport
+		expErr       error
+	}{
+		"drained": {
+			givenRelease: true,
+			expReport:    merec.ShutdownReport{Completed: 2, NotStarted: 8},
+		},
+		"canceled": {
+			expReport: merec.ShutdownReport{Cancelled: 2, NotStarted: 8},
+			expErr:    merec.ErrCtxDeadline,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			inCh := make(chan string, 10)
+			for i := 0; i < 10; i++ {
+				inCh <- strconv.Itoa(i)
+			}
+
+			started, release := make(chan struct{}), make(chan struct{})
+			control := merec.NewPoolControl()
+
+			resCh, err := merec.RunControlledWorkerPool(context.Background(), inCh,
+				blockingCall(started, release), control, 2, 0)
+			require.NoError(t, err)
+
+			<-started
+			<-started
+
+			if tc.givenRelease {
+				// The calls are released once the shutdown stopped the consuming.
+				time.AfterFunc(10*time.Millisecond, func() { close(release) })
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			report, err := control.Shutdown(ctx)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Equal(t, tc.expReport, report)
+
+			results := 0
+
+			for res := range resCh {
+				results++
+
+				if tc.givenRelease {
+					require.NoError(t, res.Err())
+				} else {
+					require.ErrorIs(t, res.Err(), merec.ErrCtxCancel)
+				}
+			}
+
+			require.Equal(t, 2, results)
+			require.Len(t, inCh, 8)
+		})
+	}
+}
+
+func TestRunControlledWorkerPool_Finished(t *testing.T) {
+	t.Parallel()
+
+	control := merec.NewPoolControl()
+
+	resCh, err := merec.RunControlledWorkerPool(context.Background(), givenCh(0), stabCall(0), control, 2, 0)
+	require.NoError(t, err)
+
+	results := 0
+	for range resCh {
+		results++
+	}
+
+	report, err := control.Shutdown(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, merec.ShutdownReport{Completed: results}, report)
+
+	_, err = merec.RunControlledWorkerPool(context.Background(), givenCh(0), stabCall(0), control, 2, 0)
+	require.ErrorIs(t, err, merec.ErrPoolControlInUse)
+
+	_, err = merec.RunControlledWorkerPool(context.Background(), givenCh(0), stabCall(0), nil, 2, 0)
+	require.ErrorIs(t, err, merec.ErrNilPoolControl)
+}
+
+func TestPoolControl_PauseResume(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenRun func(ctx context.Context, inCh <-chan string, control *merec.PoolControl) (<-chan merec.Result[int], error)
+	}{
+		"from_chan": {
+			givenRun: func(ctx context.Context, inCh <-chan string, control *merec.PoolControl) (<-chan merec.Result[int], error) {
+				return merec.RunControlledFromChan(ctx, inCh, stabCall(0), control)
+			},
+		},
+		"worker_pool": {
+			givenRun: func(ctx context.Context, inCh <-chan string, control *merec.PoolControl) (<-chan merec.Result[int], error) {
+				return merec.RunControlledWorkerPool(ctx, inCh, stabCall(0), control, 3, 0)
+			},
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			control := merec.NewPoolControl()
+			inCh := make(chan string, workLoad)
+
+			resCh, err := tc.givenRun(context.Background(), inCh, control)
+			require.NoError(t, err)
+
+			inCh <- "1"
+			require.Equal(t, 1, (<-resCh).Value())
+
+			control.Pause()
+			require.True(t, control.Paused())
+
+			for i := 0; i < workLoad; i++ {
+				inCh <- strconv.Itoa(i)
+			}
+
+			close(inCh)
+
+			select {
+			case res := <-resCh:
+				require.Failf(t, "unexpected result while paused", "%v", res)
+			case <-time.After(30 * time.Millisecond):
+			}
+
+			require.Len(t, inCh, workLoad)
+
+			control.Resume()
+			require.False(t, control.Paused())
+
+			values := make([]int, 0, workLoad)
+			for res := range resCh {
+				require.NoError(t, res.Err())
+
+				values = append(values, res.Value())
+			}
+
+			require.ElementsMatch(t, []int{0, 1, 2, 3, 4}, values)
+		})
+	}
+}
+
+func TestPoolControl_ShutdownPaused(t *testing.T) {
+	t.Parallel()
+
+	control := merec.NewPoolControl()
+	control.Pause()
+
+	resCh, err := merec.RunControlledWorkerPool(context.Background(), givenCh(workLoad), stabCall(0), control, 2, 0)
+	require.NoError(t, err)
+
+	report, err := control.Shutdown(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, report.Completed)
+
+	for res := range resCh {
+		require.Failf(t, "unexpected result after shutdown", "%v", res)
+	}
+}
+
+func TestPoolControl_ShutdownNotDrained(t *testing.T) {
+	t.Parallel()
+
+	inCh := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		inCh <- strconv.Itoa(i)
+	}
+
+	release := make(chan struct{})
+	control := merec.NewPoolControl()
+
+	// The calls ignore the cancellation, and nobody reads the output.
+	resCh, err := merec.RunControlledWorkerPool(context.Background(), inCh,
+		func(_ context.Context, in string) (int, error) {
+			<-release
+			return strconv.Atoi(in)
+		}, control, 2, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	time.Sleep(10 * time.Millisecond)
+
+	report, err := control.Shutdown(ctx)
+	require.ErrorIs(t, err, merec.ErrCtxDeadline)
+	require.Equal(t, merec.ShutdownReport{Cancelled: 2, NotStarted: 8}, report)
+
+	close(release)
+
+	for res := range resCh {
+		require.ErrorIs(t, res.Err(), merec.ErrCtxCancel)
+	}
+}
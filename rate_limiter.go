@@ -0,0 +1,136 @@
+package merec
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is the token bucket limiter. It is safe for concurrent use, so the same limiter
+// can be shared by several options and runners to cover all of them with a single quota.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	clock Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter is a constructor for the RateLimiter.
+// The rate is the number of tokens added per second, and the burst is the bucket capacity.
+// The bucket is full initially. If the clock is nil, the SystemClock is used.
+// The rate must be positive, and the burst must be at least 1.
+func NewRateLimiter(rate float64, burst int, clock Clock) (*RateLimiter, error) {
+	if !(rate > 0) || math.IsInf(rate, 1) {
+		return nil, ErrInvalidRate
+	}
+
+	if burst < 1 {
+		return nil, ErrInvalidBurst
+	}
+
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		clock:  clock,
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}, nil
+}
+
+// Wait blocks until the cost tokens are available or the context is done.
+// The waiters are served in the order they called the Wait.
+func (rl *RateLimiter) Wait(ctx context.Context, cost int) error {
+	if cost <= 0 {
+		return nil
+	}
+
+	if float64(cost) > rl.burst {
+		return ErrCostExceedsBurst
+	}
+
+	if err := CheckContext(ctx); err != nil {
+		return err
+	}
+
+	delay := rl.reserve(float64(cost))
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		rl.refund(float64(cost))
+		return CheckContext(ctx)
+	case <-rl.clock.After(delay):
+		return nil
+	}
+}
+
+// reserve takes the tokens from the bucket, even if there is not enough of them yet.
+// It returns the time to wait before the reserved tokens are refilled.
+func (rl *RateLimiter) reserve(cost float64) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refill()
+	rl.tokens -= cost
+
+	if rl.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+}
+
+// refund returns the tokens of the canceled reservation.
+func (rl *RateLimiter) refund(cost float64) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refill()
+	rl.tokens = min(rl.tokens+cost, rl.burst)
+}
+
+func (rl *RateLimiter) refill() {
+	now := rl.clock.Now()
+
+	if elapsed := now.Sub(rl.last); elapsed > 0 {
+		rl.tokens = min(rl.tokens+elapsed.Seconds()*rl.rate, rl.burst)
+	}
+
+	rl.last = now
+}
+
+type rateLimitOption[In, Out any] struct {
+	limiter *RateLimiter
+	cost    func(In) int
+}
+
+// NewRateLimitOption is a constructor for the rateLimitOption.
+// The cost function returns the number of tokens the input consumes, if it is nil every call costs one token.
+func NewRateLimitOption[In, Out any](limiter *RateLimiter, cost func(In) int) CallOption[In, Out] {
+	if cost == nil {
+		cost = func(In) int { return 1 }
+	}
+
+	return rateLimitOption[In, Out]{limiter: limiter, cost: cost}
+}
+
+// WithOption implements the CallOption interface for the rateLimitOption.
+func (rlo rateLimitOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (Out, error) {
+		if err := rlo.limiter.Wait(ctx, rlo.cost(in)); err != nil {
+			return *new(Out), err
+		}
+
+		return next(ctx, in)
+	}
+}
@@ -0,0 +1,162 @@
+package merec_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter, err := merec.NewRateLimiter(1, 2, clock)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, limiter.Wait(ctx, 1))
+	require.NoError(t, limiter.Wait(ctx, 1))
+
+	waitDone := make(chan error)
+
+	go func() {
+		waitDone <- limiter.Wait(ctx, 1)
+	}()
+
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+
+	clock.Advance(500 * time.Millisecond)
+	require.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(500 * time.Millisecond)
+	require.NoError(t, <-waitDone)
+
+	clock.Advance(10 * time.Second)
+	require.NoError(t, limiter.Wait(ctx, 2), "the bucket refills up to the burst")
+}
+
+func TestRateLimiter_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter, err := merec.NewRateLimiter(1, 1, clock)
+	require.NoError(t, err)
+
+	require.NoError(t, limiter.Wait(context.Background(), 1))
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	waitDone := make(chan error)
+
+	go func() {
+		waitDone <- limiter.Wait(ctx, 1)
+	}()
+
+	require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+
+	ctxCsl()
+	require.ErrorIs(t, <-waitDone, merec.ErrCtxCancel)
+
+	clock.Advance(time.Second)
+	require.NoError(t, limiter.Wait(context.Background(), 1), "the canceled reservation is refunded")
+}
+
+func TestRateLimiter_CostExceedsBurst(t *testing.T) {
+	t.Parallel()
+
+	limiter, err := merec.NewRateLimiter(1, 2, newFakeClock())
+	require.NoError(t, err)
+	require.ErrorIs(t, limiter.Wait(context.Background(), 3), merec.ErrCostExceedsBurst)
+}
+
+func TestRateLimitOption_SharedByRunners(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	limiter, err := merec.NewRateLimiter(10, 3, clock)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cost := func(in string) int {
+		if in == "2" {
+			return 2
+		}
+
+		return 1
+	}
+
+	firstIn, secondIn := make(chan string, 2), make(chan string, 2)
+	firstIn <- "1"
+	firstIn <- "2"
+	secondIn <- "3"
+	secondIn <- "4"
+	close(firstIn)
+	close(secondIn)
+
+	firstRes, err := merec.RunFromChan(ctx, firstIn, stabCall(0), merec.NewRateLimitOption[string, int](limiter, cost))
+	require.NoError(t, err)
+
+	secondRes, err := merec.RunWorkerPool(ctx, secondIn, stabCall(0), 2, 0, merec.NewRateLimitOption[string, int](limiter, nil))
+	require.NoError(t, err)
+
+	// The total cost is 5, so the burst of 3 lets through only a part of the calls.
+	require.Eventually(t, func() bool { return clock.Waiters() > 0 }, time.Second, time.Millisecond)
+
+	results := make([]merec.Result[int], 0, 4)
+
+	for len(results) < 4 {
+		select {
+		case r, ok := <-firstRes:
+			if ok {
+				results = append(results, r)
+			} else {
+				firstRes = nil
+			}
+		case r, ok := <-secondRes:
+			if ok {
+				results = append(results, r)
+			} else {
+				secondRes = nil
+			}
+		case <-time.After(time.Millisecond):
+			clock.Advance(100 * time.Millisecond)
+		}
+	}
+
+	require.ElementsMatch(t, []merec.Result[int]{
+		merec.ValueResult(1),
+		merec.ValueResult(2),
+		merec.ValueResult(3),
+		merec.ValueResult(4),
+	}, results)
+}
+
+func TestNewRateLimiter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenRate  float64
+		givenBurst int
+		expErr     error
+	}{
+		"zero_rate":     {givenRate: 0, givenBurst: 1, expErr: merec.ErrInvalidRate},
+		"negative_rate": {givenRate: -1, givenBurst: 1, expErr: merec.ErrInvalidRate},
+		"nan_rate":      {givenRate: math.NaN(), givenBurst: 1, expErr: merec.ErrInvalidRate},
+		"zero_burst":    {givenRate: 1, givenBurst: 0, expErr: merec.ErrInvalidBurst},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := merec.NewRateLimiter(tc.givenRate, tc.givenBurst, nil)
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
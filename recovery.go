@@ -0,0 +1,33 @@
+package merec
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+type noPanicRecoveryOption[In, Out any] struct{}
+
+// NewNoPanicRecoveryOption is a constructor for the noPanicRecoveryOption.
+// By default, the runners recover the panics of the call and return them as the ErrPanic results.
+// The option disables the recovery, so the panic crashes the process.
+func NewNoPanicRecoveryOption[In, Out any]() CallOption[In, Out] {
+	return noPanicRecoveryOption[In, Out]{}
+}
+
+// WithOption implements the CallOption interface for the noPanicRecoveryOption. It doesn't change the call.
+func (noPanicRecoveryOption[In, Out]) WithOption(next Call[In, Out]) Call[In, Out] {
+	return next
+}
+
+// recoverPanic converts the panic of the call into the PanicError.
+func recoverPanic[In, Out any](call Call[In, Out]) Call[In, Out] {
+	return func(ctx context.Context, in In) (out Out, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				out, err = *new(Out), &PanicError{Value: v, Stack: debug.Stack()}
+			}
+		}()
+
+		return call(ctx, in)
+	}
+}
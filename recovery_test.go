@@ -0,0 +1,170 @@
+package merec_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+var errPanicValue = errors.New("panic value")
+
+func panickyCall(ctx context.Context, in string) (int, error) {
+	switch in {
+	case "panic":
+		panic("boom")
+	case "panic_error":
+		panic(errPanicValue)
+	default:
+		return stabCall(0)(ctx, in)
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	runners := map[string]func(inCh chan string) (<-chan merec.Result[int], error){
+		"run_from_chan": func(inCh chan string) (<-chan merec.Result[int], error) {
+			return merec.RunFromChan(ctx, inCh, panickyCall)
+		},
+		"run_worker_pool": func(inCh chan string) (<-chan merec.Result[int], error) {
+			return merec.RunWorkerPool(ctx, inCh, panickyCall, 2, 0)
+		},
+	}
+
+	for rName, run := range runners {
+		run := run
+
+		t.Run(rName, func(t *testing.T) {
+			t.Parallel()
+
+			inCh := make(chan string, 4)
+			inCh <- "panic"
+			inCh <- "1"
+			inCh <- "panic_error"
+			inCh <- "2"
+			close(inCh)
+
+			resCh, err := run(inCh)
+			require.NoError(t, err)
+
+			var values []merec.Result[int]
+
+			var panics int
+
+			for r := range resCh {
+				if r.Err() == nil {
+					values = append(values, r)
+					continue
+				}
+
+				require.ErrorIs(t, r.Err(), merec.ErrBusinessLogic)
+				require.ErrorIs(t, r.Err(), merec.ErrPanic)
+
+				var panicErr *merec.PanicError
+				require.ErrorAs(t, r.Err(), &panicErr)
+				require.NotEmpty(t, panicErr.Stack)
+
+				panics++
+			}
+
+			require.Equal(t, 2, panics)
+			require.ElementsMatch(t, []merec.Result[int]{merec.ValueResult(1), merec.ValueResult(2)}, values)
+		})
+	}
+}
+
+func TestPanicRecovery_RunFromInput(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenIn  string
+		expValue any
+		expErr   error
+	}{
+		"panic_value": {
+			givenIn:  "panic",
+			expValue: "boom",
+			expErr:   merec.ErrPanic,
+		},
+		"panic_error": {
+			givenIn:  "panic_error",
+			expValue: errPanicValue,
+			expErr:   errPanicValue,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			resCh, err := merec.RunFromInput(context.Background(), tc.givenIn, panickyCall)
+			require.NoError(t, err)
+
+			res := <-resCh
+			require.ErrorIs(t, res.Err(), merec.ErrPanic)
+			require.ErrorIs(t, res.Err(), tc.expErr)
+
+			var panicErr *merec.PanicError
+			require.ErrorAs(t, res.Err(), &panicErr)
+			require.Equal(t, tc.expValue, panicErr.Value)
+		})
+	}
+}
+
+func TestNoPanicRecoveryOption(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv("MEREC_NO_PANIC_RECOVERY") == "1" {
+		resCh, _ := merec.RunFromInput(
+			context.Background(), "panic", panickyCall, merec.NewNoPanicRecoveryOption[string, int](),
+		)
+		<-resCh
+
+		return
+	}
+
+	//nolint:gosec // reason: the test binary re-runs itself.
+	cmd := exec.Command(os.Args[0], "-test.run=^TestNoPanicRecoveryOption$")
+	cmd.Env = append(os.Environ(), "MEREC_NO_PANIC_RECOVERY=1")
+
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Contains(t, string(out), "panic: boom", strconv.Quote(string(out)))
+}
+
+func TestPanicRecovery_SeenByOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	breaker := merec.NewCircuitBreaker(merec.CircuitBreakerConfig{FailureThreshold: 1, HalfOpenProbes: 1})
+	option := merec.NewCircuitBreakerOption[string, int](breaker)
+
+	for _, in := range []string{"panic", "panic"} {
+		resCh, err := merec.RunFromInput(ctx, in, panickyCall, option)
+		require.NoError(t, err)
+
+		res := <-resCh
+		require.ErrorIs(t, res.Err(), merec.ErrPanic)
+		require.NotErrorIs(t, res.Err(), merec.ErrCircuitOpen, "the panicking probe is recorded as a failure")
+	}
+
+	resCh, err := merec.RunFromInput(ctx, "1", panickyCall, option)
+	require.NoError(t, err)
+
+	res := <-resCh
+	require.NoError(t, res.Err())
+	require.Equal(t, merec.CircuitClosed, breaker.State())
+}
@@ -0,0 +1,144 @@
+package merec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BatchCall is the function to be executed with the batch of inputs.
+// It must return exactly one value for every input, in the same order.
+type BatchCall[In, Out any] func(context.Context, []In) ([]Out, error)
+
+// RunBatched starts a separate goroutine to consume from the input channel and execute the call function with
+// the batches of up to batchSize inputs, or whatever has arrived within the linger after the first input of the batch.
+// Returns the channel to be listened to, to get the Result values, one for every input in the input order.
+// The batch error is applied to every input of the batch, the options wrap the call of the whole batch.
+// Once the context is done, it stops consuming, drops the pending batch and the results of the call in flight,
+// and finishes with the ErrCtxCancel or ErrCtxDeadline result, waiting a little for the consumer if the output is full.
+func RunBatched[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call BatchCall[In, Out],
+	batchSize int,
+	linger time.Duration,
+	options ...CallOption[[]In, []Out],
+) (<-chan Result[Out], error) {
+	var batchCall Call[[]In, []Out]
+	if call != nil {
+		batchCall = withOptions(Call[[]In, []Out](call), options)
+	}
+
+	return runBatched(ctx, inCh, batchCall, batchSize, linger)
+}
+
+func runBatched[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[[]In, []Out],
+	batchSize int,
+	linger time.Duration,
+) (<-chan Result[Out], error) {
+	if err := validateRunBatchedInputs(ctx, inCh, call); err != nil {
+		return nil, err
+	}
+
+	batchSize = max(batchSize, 1)
+
+	resCh := newResCh[Out](max(cap(inCh), batchSize))
+
+	go func() {
+		defer close(resCh)
+
+		for {
+			batch, more := collectBatch(ctx, inCh, batchSize, linger)
+			if len(batch) > 0 && !emitBatch(ctx, call, batch, resCh) {
+				break
+			}
+
+			if !more {
+				break
+			}
+		}
+
+		sendCtxErr(ctx, resCh)
+	}()
+
+	return resCh, nil
+}
+
+// collectBatch waits for the first input, and then collects the batch until it is full, or the linger passes.
+// Returns false if the input channel is closed or the context is done. The batch is dropped in the latter case.
+func collectBatch[In any](ctx context.Context, inCh <-chan In, batchSize int, linger time.Duration) ([]In, bool) {
+	var batch []In
+
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case in, ok := <-inCh:
+		if !ok {
+			return nil, false
+		}
+
+		batch = append(make([]In, 0, batchSize), in)
+	}
+
+	timer := time.NewTimer(linger)
+	defer timer.Stop()
+
+	for len(batch) < batchSize {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-timer.C:
+			return batch, true
+		case in, ok := <-inCh:
+			if !ok {
+				return batch, false
+			}
+
+			batch = append(batch, in)
+		}
+	}
+
+	return batch, true
+}
+
+// emitBatch executes the call with the batch and sends the result of every input.
+// Returns false if the processing must be interrupted because of the context or the ErrMustStop.
+func emitBatch[In, Out any](ctx context.Context, call Call[[]In, []Out], batch []In, resCh chan<- Result[Out]) bool {
+	outs, err := call(ctx, batch)
+	if err == nil && len(outs) != len(batch) {
+		err = fmt.Errorf("%w: %d values for %d inputs", ErrBatchSizeMismatch, len(outs), len(batch))
+	}
+
+	for i := range batch {
+		res := ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, err))
+		if err == nil {
+			res = ValueResult(outs[i])
+		}
+
+		if !sendContext(ctx, resCh, res) {
+			return false
+		}
+	}
+
+	return !errors.Is(err, ErrMustStop)
+}
+
+func validateRunBatchedInputs[In, Out any](ctx context.Context, inCh <-chan In, call Call[[]In, []Out]) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+
+	if inCh == nil {
+		return ErrNilInChan
+	}
+
+	if call == nil {
+		return ErrNilCallFunc
+	}
+
+	return nil
+}
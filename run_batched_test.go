@@ -0,0 +1,175 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestRunBatched(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenBatchSize int
+		givenLinger    time.Duration
+		givenPause     time.Duration
+		expBatches     []int
+	}{
+		"full_batches": {
+			givenBatchSize: 2,
+			givenLinger:    time.Hour,
+			expBatches:     []int{2, 2, 1},
+		},
+		"linger_passed": {
+			givenBatchSize: workLoad,
+			givenLinger:    10 * time.Millisecond,
+			givenPause:     50 * time.Millisecond,
+			expBatches:     []int{1, 1, 1, 1, 1},
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu      sync.Mutex
+				batches []int
+			)
+
+			call := func(ctx context.Context, ins []string) ([]int, error) {
+				mu.Lock()
+				batches = append(batches, len(ins))
+				mu.Unlock()
+
+				outs := make([]int, len(ins))
+				for i, in := range ins {
+					outs[i], _ = strconv.Atoi(in)
+				}
+
+				return outs, nil
+			}
+
+			inCh := make(chan string)
+
+			go func() {
+				defer close(inCh)
+
+				for i := 0; i < workLoad; i++ {
+					inCh <- strconv.Itoa(i)
+					time.Sleep(tc.givenPause)
+				}
+			}()
+
+			resCh, err := merec.RunBatched[string, int](ctx, inCh, call, tc.givenBatchSize, tc.givenLinger)
+			require.NoError(t, err)
+
+			results := make([]merec.Result[int], 0, workLoad)
+
+			for res := range resCh {
+				results = append(results, res)
+			}
+
+			require.Equal(t, expectedResults(), results)
+			require.Equal(t, tc.expBatches, batches)
+		})
+	}
+}
+
+func TestRunBatched_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenCall merec.BatchCall[string, int]
+		expErr    error
+	}{
+		"batch_error": {
+			givenCall: func(context.Context, []string) ([]int, error) { return nil, errFlaky },
+			expErr:    errFlaky,
+		},
+		"size_mismatch": {
+			givenCall: func(context.Context, []string) ([]int, error) { return []int{1}, nil },
+			expErr:    merec.ErrBatchSizeMismatch,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			resCh, err := merec.RunBatched(ctx, givenCh(0), tc.givenCall, 3, time.Hour)
+			require.NoError(t, err)
+
+			count := 0
+
+			for res := range resCh {
+				require.ErrorIs(t, res.Err(), merec.ErrBusinessLogic)
+				require.ErrorIs(t, res.Err(), tc.expErr)
+
+				count++
+			}
+
+			require.Equal(t, workLoad, count)
+		})
+	}
+
+	_, err := merec.RunBatched[string, int](ctx, givenCh(0), nil, 3, time.Hour)
+	require.ErrorIs(t, err, merec.ErrNilCallFunc)
+}
+
+func TestRunBatched_MustStop(t *testing.T) {
+	t.Parallel()
+
+	call := func(context.Context, []string) ([]int, error) { return nil, merec.ErrMustStop }
+
+	resCh, err := merec.RunBatched(context.Background(), endlessCh(workLoad), call, 2, time.Hour)
+	require.NoError(t, err)
+
+	results := make([]merec.Result[int], 0, 2)
+
+	for res := range resCh {
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 2)
+
+	for _, res := range results {
+		require.ErrorIs(t, res.Err(), merec.ErrMustStop)
+	}
+}
+
+func TestRunBatched_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+
+	call := func(context.Context, []string) ([]int, error) { return make([]int, 2), nil }
+
+	resCh, err := merec.RunBatched(ctx, endlessCh(1), call, 2, time.Hour)
+	require.NoError(t, err)
+
+	ctxCsl()
+
+	results := make([]merec.Result[int], 0, 1)
+
+	for res := range resCh {
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err(), merec.ErrCtxCancel)
+}
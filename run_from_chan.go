@@ -4,55 +4,132 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 )
 
 // RunFromChan starts a separate goroutine to consume from the input channel and execute the call function with it.
 // Returns the channel to be listened to, to get the Result values.
 // Executions are independent, and it doesn't stop processing inputs if call fails.
+// Once the context is done, it stops consuming, drops the results of the calls in flight,
+// and finishes with the ErrCtxCancel or ErrCtxDeadline result, waiting a little for the consumer if the output is full.
 func RunFromChan[In, Out any](
 	ctx context.Context,
 	inCh <-chan In,
 	call Call[In, Out],
 	options ...CallOption[In, Out],
 ) (<-chan Result[Out], error) {
-	next := call
-
-	for _, o := range options {
-		next = o.WithOption(next)
-	}
-
-	return runFromChan(ctx, inCh, next)
+	return runFromChan(ctx, inCh, withOptions(call, options), nil)
 }
 
-func runFromChan[In, Out any](ctx context.Context, inCh <-chan In, call Call[In, Out]) (<-chan Result[Out], error) {
+func runFromChan[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	control *PoolControl,
+) (<-chan Result[Out], error) {
 	if err := validateRunFromChanInputs(ctx, inCh, call); err != nil {
 		return nil, err
 	}
 
-	resCh := make(chan Result[Out], cap(inCh))
+	var quit <-chan struct{}
+
+	if control != nil {
+		if err := control.attach(func() int { return len(inCh) }); err != nil {
+			return nil, err
+		}
+
+		call, quit = withControl(control, call), control.quit
+	}
+
+	resCh := newResCh[Out](cap(inCh))
 
 	go func() {
 		defer close(resCh)
 
-		for in := range inCh {
-			res, err := call(ctx, in)
-			if errors.Is(err, ErrMustStop) {
-				resCh <- ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, err))
-				return
-			}
+		var seq atomic.Uint64
 
-			if err != nil {
-				resCh <- ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, err))
-				continue
-			}
+		consume(withWorker(ctx, &seq, 0), inCh, call, resCh, quit, control)
 
-			resCh <- ValueResult[Out](res)
+		if control != nil {
+			control.finish()
 		}
+
+		sendCtxErr(ctx, resCh)
 	}()
 
 	return resCh, nil
 }
 
+// consume executes the call with the inputs until the input channel is closed, the context is done,
+// or the quit channel is closed. The nil quit channel never interrupts the consuming.
+// While the control is paused, no new inputs are taken. The nil control is never paused.
+// Returns true if the call requested to interrupt the processing with the ErrMustStop.
+func consume[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	resCh chan<- Result[Out],
+	quit <-chan struct{},
+	control *PoolControl,
+) bool {
+	for {
+		if CheckContext(ctx) != nil || TryReedSignal(quit) {
+			return false
+		}
+
+		paused, resumed := control.signals()
+
+		if TryReedSignal(paused) {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-quit:
+				return false
+			case <-resumed:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-quit:
+			return false
+		case <-paused:
+			continue
+		case in, ok := <-inCh:
+			if !ok {
+				return false
+			}
+
+			res, mustStop := execute(ctx, call, in)
+			if !sendContext(ctx, resCh, res) {
+				return false
+			}
+
+			if mustStop {
+				return true
+			}
+		}
+	}
+}
+
+// execute runs the call and converts its outcome into the Result.
+// Returns true if the call requested to interrupt the processing with the ErrMustStop.
+// The metadata collected by the options is attached to the Result.
+func execute[In, Out any](ctx context.Context, call Call[In, Out], in In) (Result[Out], bool) {
+	ctx, slot := withMetaSlot(ctx)
+
+	res, err := call(ctx, in)
+	meta := finishMeta(slot)
+
+	if err != nil {
+		return Result[Out]{err: fmt.Errorf("%w: %w", ErrBusinessLogic, err), meta: meta}, errors.Is(err, ErrMustStop)
+	}
+
+	return Result[Out]{value: res, meta: meta}, false
+}
+
 func validateRunFromChanInputs[In, Out any](ctx context.Context, inCh <-chan In, call Call[In, Out]) error {
 	if ctx == nil {
 		return ErrNilContext
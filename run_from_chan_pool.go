@@ -2,14 +2,15 @@ package merec
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // RunWorkerPool starts the pool of goroutine workers to consume from the input channel and execute
 // the call functions with inputs. Returns the channel to be listened to, to get the Result values.
 // Executions are independent, and it doesn't stop processing inputs if call fails.
+// Once the context is done, it stops consuming, drops the results of the calls in flight,
+// and finishes with the ErrCtxCancel or ErrCtxDeadline result, waiting a little for the consumer if the output is full.
 func RunWorkerPool[In, Out any](
 	ctx context.Context,
 	inCh <-chan In,
@@ -18,13 +19,7 @@ func RunWorkerPool[In, Out any](
 	bufSize int,
 	options ...CallOption[In, Out],
 ) (<-chan Result[Out], error) {
-	next := call
-
-	for _, o := range options {
-		next = o.WithOption(next)
-	}
-
-	return runWorkerPool(ctx, inCh, next, poolSize, bufSize)
+	return runWorkerPool(ctx, inCh, withOptions(call, options), poolSize, bufSize, nil)
 }
 
 func runWorkerPool[In, Out any](
@@ -33,42 +28,54 @@ func runWorkerPool[In, Out any](
 	call Call[In, Out],
 	poolSize int,
 	bufSize int,
+	control *PoolControl,
 ) (<-chan Result[Out], error) {
 	if err := validateRunFromChanInputs(ctx, inCh, call); err != nil {
 		return nil, err
 	}
 
-	ctx, ctxCsl := context.WithCancel(ctx)
+	var quit <-chan struct{}
 
-	resChanPool := SpawnResChanPool[Result[Out]](poolSize, bufSize)
+	if control != nil {
+		if err := control.attach(func() int { return len(inCh) }); err != nil {
+			return nil, err
+		}
 
-	worker := func(resCh chan Result[Out]) {
-		defer close(resCh)
+		call, quit = withControl(control, call), control.quit
+	}
 
-		for in := range inCh {
-			res, err := call(ctx, in)
-			if errors.Is(err, ErrMustStop) {
-				resCh <- ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, err))
+	poolCtx, poolCtxCsl := context.WithCancel(ctx)
 
-				ctxCsl()
+	resChanPool := SpawnResChanPool[Result[Out]](poolSize, bufSize)
 
-				return
-			}
+	var (
+		seq atomic.Uint64
+		wg  sync.WaitGroup
+	)
 
-			if err != nil {
-				resCh <- ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, err))
-				continue
-			}
+	wg.Add(poolSize)
+
+	worker := func(id int, resCh chan Result[Out]) {
+		defer wg.Done()
+		defer close(resCh)
 
-			resCh <- ValueResult[Out](res)
+		if consume(withWorker(poolCtx, &seq, id), inCh, call, resCh, quit, control) {
+			poolCtxCsl()
 		}
 	}
 
 	for i := 0; i < poolSize; i++ {
-		go worker(resChanPool[i])
+		go worker(i, resChanPool[i])
+	}
+
+	if control != nil {
+		go func() {
+			wg.Wait()
+			control.finish()
+		}()
 	}
 
-	return MergeChanPool(resChanPool), nil
+	return mergeChanPoolContext(ctx, resChanPool, poolCtxCsl), nil
 }
 
 // SpawnResChanPool creates as many channels as it is needed.
@@ -110,6 +117,41 @@ func MergeChanPool[T any](resChanPool []chan T) chan T {
 	return mergeCh
 }
 
+// mergeChanPoolContext combines the output from list of channels into a single one, like the MergeChanPool.
+// Once the context is done, it drops the rest of the values. When all the channels are closed,
+// it calls the done function, and sends the final context error.
+func mergeChanPoolContext[Out any](
+	ctx context.Context,
+	resChanPool []chan Result[Out],
+	done context.CancelFunc,
+) chan Result[Out] {
+	mergeCh := newResCh[Out](len(resChanPool))
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(resChanPool))
+
+	for _, rCh := range resChanPool {
+		go func(resCh chan Result[Out]) {
+			defer wg.Done()
+
+			for out := range resCh {
+				sendContext(ctx, mergeCh, out)
+			}
+		}(rCh)
+	}
+
+	go func() {
+		wg.Wait()
+		done()
+
+		sendCtxErr(ctx, mergeCh)
+		close(mergeCh)
+	}()
+
+	return mergeCh
+}
+
 // MergeSignalChanPool combines the output from lit of channels into a single one.
 // Implements concurrency pattern FanIn. In addition, it takes the value from the chan only
 // if there is a corresponding signal.
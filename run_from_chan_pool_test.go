@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"strconv"
 	"testing"
 	"time"
@@ -249,7 +250,7 @@ func TestRunFromPool_WithOptions(t *testing.T) {
 			expRes: []merec.Result[int]{
 				merec.ErrorResult[int](
 					fmt.Errorf("%w: %w", merec.ErrBusinessLogic,
-						fmt.Errorf("%w: %w", merec.ErrMustStop, errCtxDeadline),
+						&merec.FailFastError{Failures: 1, Calls: 1, Err: errCtxDeadline},
 					),
 				),
 			},
@@ -284,3 +285,80 @@ func balancedWorkload(inChSize int) int {
 
 	return poolSize
 }
+
+func TestRunFromPool_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenCtx func() (context.Context, context.CancelFunc)
+		expErr   error
+	}{
+		"cancel": {
+			givenCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			expErr: merec.ErrCtxCancel,
+		},
+		"deadline": {
+			givenCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			expErr: merec.ErrCtxDeadline,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, ctxCsl := tc.givenCtx()
+			defer ctxCsl()
+
+			inCh := make(chan string)
+
+			go func() {
+				for i := 0; ; i++ {
+					select {
+					case inCh <- strconv.Itoa(i):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			resCh, err := merec.RunWorkerPool(ctx, inCh, stabCall(time.Millisecond), 3, 0)
+			require.NoError(t, err)
+
+			var last merec.Result[int]
+
+			for r := range resCh {
+				last = r
+
+				if tc.expErr == merec.ErrCtxCancel && r.Err() == nil {
+					ctxCsl()
+				}
+			}
+
+			require.ErrorIs(t, last.Err(), tc.expErr)
+			require.NotErrorIs(t, last.Err(), merec.ErrBusinessLogic)
+		})
+	}
+}
+
+//nolint:paralleltest // reason: counts the goroutines of the whole process.
+func TestRunFromPool_NoGoroutineLeak(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+
+	// Nobody reads the results, so the runner gets blocked on the output.
+	_, err := merec.RunWorkerPool(ctx, endlessCh(workLoad), stabCall(0), 3, 0)
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	ctxCsl()
+
+	requireNoGoroutineLeak(t, baseline)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"strconv"
 	"testing"
 	"time"
@@ -249,7 +250,7 @@ func TestRunFromChan_WithOptions(t *testing.T) {
 			expRes: []merec.Result[int]{
 				merec.ErrorResult[int](
 					fmt.Errorf("%w: %w", merec.ErrBusinessLogic,
-						fmt.Errorf("%w: %w", merec.ErrMustStop, errCtxDeadline),
+						&merec.FailFastError{Failures: 1, Calls: 1, Err: errCtxDeadline},
 					),
 				),
 			},
@@ -275,3 +276,170 @@ func TestRunFromChan_WithOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestRunFromChan_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenCtx func() (context.Context, context.CancelFunc)
+		expErr   error
+	}{
+		"cancel": {
+			givenCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			expErr: merec.ErrCtxCancel,
+		},
+		"deadline": {
+			givenCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			expErr: merec.ErrCtxDeadline,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, ctxCsl := tc.givenCtx()
+			defer ctxCsl()
+
+			inCh := make(chan string)
+
+			go func() {
+				for i := 0; ; i++ {
+					select {
+					case inCh <- strconv.Itoa(i):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			resCh, err := merec.RunFromChan(ctx, inCh, stabCall(time.Millisecond))
+			require.NoError(t, err)
+
+			var last merec.Result[int]
+
+			for r := range resCh {
+				last = r
+
+				if tc.expErr == merec.ErrCtxCancel && r.Err() == nil {
+					ctxCsl()
+				}
+			}
+
+			require.ErrorIs(t, last.Err(), tc.expErr)
+			require.NotErrorIs(t, last.Err(), merec.ErrBusinessLogic)
+		})
+	}
+}
+
+func TestRunFromChan_ContextDoneUnreadOutput(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenRun func(context.Context, <-chan string) (<-chan merec.Result[int], error)
+	}{
+		"from_chan": {
+			givenRun: func(ctx context.Context, inCh <-chan string) (<-chan merec.Result[int], error) {
+				return merec.RunFromChan(ctx, inCh, stabCall(0))
+			},
+		},
+		"worker_pool": {
+			givenRun: func(ctx context.Context, inCh <-chan string) (<-chan merec.Result[int], error) {
+				return merec.RunWorkerPool(ctx, inCh, stabCall(0), 1, 0)
+			},
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, ctxCsl := context.WithCancel(context.Background())
+			defer ctxCsl()
+
+			inCh := make(chan string)
+
+			resCh, err := tc.givenRun(ctx, inCh)
+			require.NoError(t, err)
+
+			// The output is full of the unread results when the context is canceled.
+			go func() {
+				for i := 0; ; i++ {
+					select {
+					case inCh <- strconv.Itoa(i):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			ctxCsl()
+
+			// The runner finishes before the consumer comes back to the output.
+			time.Sleep(10 * time.Millisecond)
+
+			var last merec.Result[int]
+			for r := range resCh {
+				last = r
+			}
+
+			require.ErrorIs(t, last.Err(), merec.ErrCtxCancel)
+		})
+	}
+}
+
+func TestRunFromChan_ContextDoneFullOutput(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	defer ctxCsl()
+
+	inCh := endlessCh(3)
+
+	resCh, err := merec.RunFromChan(ctx, inCh, stabCall(0))
+	require.NoError(t, err)
+
+	// The output is full of the results of the finished calls when the context is canceled.
+	inCh <- "3"
+
+	time.Sleep(10 * time.Millisecond)
+	ctxCsl()
+
+	results := make([]merec.Result[int], 0, 5)
+	for r := range resCh {
+		results = append(results, r)
+	}
+
+	require.Equal(t, []merec.Result[int]{
+		merec.ValueResult(0),
+		merec.ValueResult(1),
+		merec.ValueResult(2),
+		merec.ValueResult(3),
+		merec.ErrorResult[int](merec.ErrCtxCancel),
+	}, results)
+}
+
+//nolint:paralleltest // reason: counts the goroutines of the whole process.
+func TestRunFromChan_NoGoroutineLeak(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+
+	// Nobody reads the results, so the runner gets blocked on the output.
+	_, err := merec.RunFromChan(ctx, endlessCh(workLoad), stabCall(0))
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+	ctxCsl()
+
+	requireNoGoroutineLeak(t, baseline)
+}
@@ -1,9 +1,6 @@
 package merec
 
-import (
-	"context"
-	"fmt"
-)
+import "context"
 
 // RunFromInput executes the call function in a separate goroutine with the specified input.
 // Returns the channel to be listened to, to get the Result value.
@@ -13,13 +10,7 @@ func RunFromInput[In, Out any](
 	call Call[In, Out],
 	options ...CallOption[In, Out],
 ) (<-chan Result[Out], error) {
-	next := call
-
-	for _, o := range options {
-		next = o.WithOption(next)
-	}
-
-	return runFromInput(ctx, in, next)
+	return runFromInput(ctx, in, withOptions(call, options))
 }
 
 func runFromInput[In, Out any](ctx context.Context, in In, call Call[In, Out]) (<-chan Result[Out], error) {
@@ -32,17 +23,35 @@ func runFromInput[In, Out any](ctx context.Context, in In, call Call[In, Out]) (
 	go func() {
 		defer close(resCh)
 
-		res, err := call(ctx, in)
-		if err != nil {
-			resCh <- ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, err))
+		res, _ := execute(ctx, call, in)
+		resCh <- res
+	}()
 
-			return
-		}
+	return resCh, nil
+}
 
-		resCh <- ValueResult[Out](res)
+// RunFuture executes the call function in a separate goroutine with the specified input, like the RunFromInput.
+// Returns the Future to wait for the Result.
+func RunFuture[In, Out any](
+	ctx context.Context,
+	in In,
+	call Call[In, Out],
+	options ...CallOption[In, Out],
+) (Future[Out], error) {
+	call = withOptions(call, options)
+
+	if err := validateRunFromInputInputs(ctx, call); err != nil {
+		return Future[Out]{}, err
+	}
+
+	future, resolve := newFuture[Out]()
+
+	go func() {
+		res, _ := execute(ctx, call, in)
+		resolve(res)
 	}()
 
-	return resCh, nil
+	return future, nil
 }
 
 func validateRunFromInputInputs[In, Out any](ctx context.Context, call Call[In, Out]) error {
@@ -26,8 +26,10 @@ func TestRunFromInput(t *testing.T) {
 		},
 		"timeout": {
 			givenCtx: func(ctx context.Context) (context.Context, context.CancelFunc) {
-				//nolint:govet // reason: that is a test, we don't care.
-				ctx, _ = context.WithTimeout(ctx, 100*time.Microsecond)
+				// The deadline must expire on its own, so the cancel is released only after that.
+				ctx, ctxCsl := context.WithTimeout(ctx, 100*time.Microsecond)
+				context.AfterFunc(ctx, ctxCsl)
+
 				return ctx, nil
 			},
 			givenIn:   "1",
@@ -0,0 +1,95 @@
+package merec
+
+import (
+	"context"
+	"hash/maphash"
+	"sync/atomic"
+)
+
+// RunKeyedWorkerPool starts the pool of goroutine workers to consume from the input channel and execute
+// the call functions with inputs, like the RunWorkerPool. Unlike it, the inputs with the same key are always routed
+// to the same worker, so their results are emitted in the input order, while the different keys run in parallel.
+func RunKeyedWorkerPool[In, Out any, K comparable](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	keyOf func(In) K,
+	poolSize int,
+	bufSize int,
+	options ...CallOption[In, Out],
+) (<-chan Result[Out], error) {
+	return runKeyedWorkerPool(ctx, inCh, withOptions(call, options), keyOf, poolSize, bufSize)
+}
+
+func runKeyedWorkerPool[In, Out any, K comparable](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	keyOf func(In) K,
+	poolSize int,
+	bufSize int,
+) (<-chan Result[Out], error) {
+	if err := validateRunFromChanInputs(ctx, inCh, call); err != nil {
+		return nil, err
+	}
+
+	if keyOf == nil {
+		return nil, ErrNilKeyFunc
+	}
+
+	if poolSize < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+
+	poolCtx, poolCtxCsl := context.WithCancel(ctx)
+
+	laneChanPool := SpawnResChanPool[In](poolSize, bufSize)
+	resChanPool := SpawnResChanPool[Result[Out]](poolSize, bufSize)
+
+	go routeByKey(poolCtx, inCh, keyOf, laneChanPool)
+
+	var seq atomic.Uint64
+
+	worker := func(id int, laneCh <-chan In, resCh chan Result[Out]) {
+		defer close(resCh)
+
+		if consume(withWorker(poolCtx, &seq, id), laneCh, call, resCh, nil, nil) {
+			poolCtxCsl()
+		}
+	}
+
+	for i := 0; i < poolSize; i++ {
+		go worker(i, laneChanPool[i], resChanPool[i])
+	}
+
+	return mergeChanPoolContext(ctx, resChanPool, poolCtxCsl), nil
+}
+
+// routeByKey sends every input to the lane chosen by the hash of its key.
+// The lanes are closed when the input channel is closed or the context is done.
+func routeByKey[In any, K comparable](ctx context.Context, inCh <-chan In, keyOf func(In) K, laneChanPool []chan In) {
+	defer func() {
+		for _, laneCh := range laneChanPool {
+			close(laneCh)
+		}
+	}()
+
+	seed := maphash.MakeSeed()
+	lanes := uint64(len(laneChanPool))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case in, ok := <-inCh:
+			if !ok {
+				return
+			}
+
+			lane := maphash.Comparable(seed, keyOf(in)) % lanes
+			if !sendContext(ctx, laneChanPool[lane], in) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package merec_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestRunKeyedWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	const (
+		keys   = 5
+		events = 20
+	)
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenPoolSize int
+		givenBufSize  int
+	}{
+		"single_worker": {
+			givenPoolSize: 1,
+		},
+		"fewer_workers_than_keys": {
+			givenPoolSize: 3,
+		},
+		"more_workers_than_keys": {
+			givenPoolSize: 8,
+			givenBufSize:  events,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			inCh := make(chan string)
+
+			go func() {
+				defer close(inCh)
+
+				for e := 0; e < events; e++ {
+					for k := 0; k < keys; k++ {
+						inCh <- fmt.Sprintf("%d/%d", k, e)
+					}
+				}
+			}()
+
+			call := func(ctx context.Context, in string) (string, error) {
+				time.Sleep(time.Duration(rand.IntN(100)) * time.Microsecond)
+				return in, nil
+			}
+			keyOf := func(in string) string {
+				key, _, _ := strings.Cut(in, "/")
+				return key
+			}
+
+			resCh, err := merec.RunKeyedWorkerPool(ctx, inCh, call, keyOf, tc.givenPoolSize, tc.givenBufSize)
+			require.NoError(t, err)
+
+			perKey := make(map[string][]string)
+
+			for r := range resCh {
+				require.NoError(t, r.Err())
+				perKey[keyOf(r.Value())] = append(perKey[keyOf(r.Value())], r.Value())
+			}
+
+			require.Len(t, perKey, keys)
+
+			for k := 0; k < keys; k++ {
+				expValues := make([]string, events)
+				for e := range expValues {
+					expValues[e] = fmt.Sprintf("%d/%d", k, e)
+				}
+
+				require.Equal(t, expValues, perKey[fmt.Sprint(k)])
+			}
+		})
+	}
+}
+
+func TestRunKeyedWorkerPool_MustStop(t *testing.T) {
+	t.Parallel()
+
+	inCh := endlessCh(workLoad)
+	keyOf := func(in string) string { return in }
+	options := []merec.CallOption[string, int]{
+		merec.NewFailFastOptionOption[string, int](1),
+	}
+
+	resCh, err := merec.RunKeyedWorkerPool(context.Background(), inCh, stabCall(0), keyOf, 2, 0, options...)
+	require.NoError(t, err)
+
+	inCh <- "qwerty"
+
+	var stopped bool
+
+	for r := range resCh {
+		if r.Err() != nil {
+			require.ErrorIs(t, r.Err(), merec.ErrMustStop)
+
+			stopped = true
+		}
+	}
+
+	require.True(t, stopped)
+}
+
+func TestRunKeyedWorkerPool_ValidationFail(t *testing.T) {
+	t.Parallel()
+
+	keyOf := func(in string) string { return in }
+
+	testCases := map[string]struct {
+		givenCtx   context.Context
+		givenIn    chan string
+		givenCall  merec.Call[string, int]
+		givenKeyOf func(string) string
+		givenSize  int
+		expErr     error
+	}{
+		"nil_ctx": {
+			givenIn:    givenCh(0),
+			givenCall:  stabCall(time.Second),
+			givenKeyOf: keyOf,
+			givenSize:  1,
+			expErr:     merec.ErrNilContext,
+		},
+		"nil_in_chan": {
+			givenCtx:   context.Background(),
+			givenCall:  stabCall(time.Second),
+			givenKeyOf: keyOf,
+			givenSize:  1,
+			expErr:     merec.ErrNilInChan,
+		},
+		"nil_call_function": {
+			givenCtx:   context.Background(),
+			givenIn:    givenCh(0),
+			givenKeyOf: keyOf,
+			givenSize:  1,
+			expErr:     merec.ErrNilCallFunc,
+		},
+		"nil_key_function": {
+			givenCtx:  context.Background(),
+			givenIn:   givenCh(0),
+			givenCall: stabCall(time.Second),
+			givenSize: 1,
+			expErr:    merec.ErrNilKeyFunc,
+		},
+		"invalid_pool_size": {
+			givenCtx:   context.Background(),
+			givenIn:    givenCh(0),
+			givenCall:  stabCall(time.Second),
+			givenKeyOf: keyOf,
+			givenSize:  0,
+			expErr:     merec.ErrInvalidPoolSize,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			resCh, err := merec.RunKeyedWorkerPool(tc.givenCtx, tc.givenIn, tc.givenCall, tc.givenKeyOf, tc.givenSize, 0)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Nil(t, resCh)
+		})
+	}
+}
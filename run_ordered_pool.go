@@ -0,0 +1,228 @@
+package merec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ReorderPolicy defines the behavior of the RunOrderedWorkerPool when the reorder window is full.
+type ReorderPolicy int
+
+// The list of supported reorder policies.
+const (
+	// ReorderBlock stops consuming the inputs until the oldest pending result is emitted.
+	ReorderBlock ReorderPolicy = iota
+	// ReorderReject doesn't execute the call for the input and immediately emits the ErrReorderWindowFull result.
+	// Such results are not ordered.
+	ReorderReject
+)
+
+type seqInput[In any] struct {
+	seq uint64
+	in  In
+}
+
+type seqResult[Out any] struct {
+	seq     uint64
+	ordered bool
+	res     Result[Out]
+}
+
+// RunOrderedWorkerPool starts the pool of goroutine workers to consume from the input channel and execute
+// the call functions with inputs, like the RunWorkerPool. Unlike it, the Result values are emitted in the input order.
+// The windowSize limits the number of inputs being executed or waiting for the older ones to be emitted,
+// and the policy defines what happens to the inputs when the window is full.
+func RunOrderedWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	poolSize int,
+	windowSize int,
+	policy ReorderPolicy,
+	options ...CallOption[In, Out],
+) (<-chan Result[Out], error) {
+	return runOrderedWorkerPool(ctx, inCh, withOptions(call, options), poolSize, windowSize, policy)
+}
+
+func runOrderedWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	poolSize int,
+	windowSize int,
+	policy ReorderPolicy,
+) (<-chan Result[Out], error) {
+	if err := validateRunFromChanInputs(ctx, inCh, call); err != nil {
+		return nil, err
+	}
+
+	if poolSize < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+
+	poolCtx, poolCtxCsl := context.WithCancel(ctx)
+
+	windowSize = max(windowSize, 1)
+	slots := make(chan struct{}, windowSize)
+	taskCh := make(chan seqInput[In])
+	doneCh := make(chan seqResult[Out], poolSize)
+
+	var wg sync.WaitGroup
+
+	wg.Add(poolSize + 1)
+
+	go func() {
+		defer wg.Done()
+		defer close(taskCh)
+
+		dispatchOrdered(poolCtx, inCh, taskCh, doneCh, slots, policy)
+	}()
+
+	var seq atomic.Uint64
+
+	worker := func(id int) {
+		defer wg.Done()
+
+		workerCtx := withWorker(poolCtx, &seq, id)
+
+		for {
+			select {
+			case <-poolCtx.Done():
+				return
+			case task, ok := <-taskCh:
+				if !ok {
+					return
+				}
+
+				res, mustStop := execute(workerCtx, call, task.in)
+				doneCh <- seqResult[Out]{seq: task.seq, ordered: true, res: res}
+
+				if mustStop {
+					poolCtxCsl()
+					return
+				}
+			}
+		}
+	}
+
+	for i := 0; i < poolSize; i++ {
+		go worker(i)
+	}
+
+	go func() {
+		wg.Wait()
+		close(doneCh)
+	}()
+
+	resCh := newResCh[Out](0)
+
+	go func() {
+		defer close(resCh)
+
+		emitOrdered(ctx, doneCh, resCh, slots)
+		poolCtxCsl()
+
+		sendCtxErr(ctx, resCh)
+	}()
+
+	return resCh, nil
+}
+
+// dispatchOrdered numbers the inputs and passes them to the workers while there is room in the window.
+func dispatchOrdered[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	taskCh chan<- seqInput[In],
+	doneCh chan<- seqResult[Out],
+	slots chan struct{},
+	policy ReorderPolicy,
+) {
+	var seq uint64
+
+	for {
+		var (
+			in In
+			ok bool
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case in, ok = <-inCh:
+			if !ok {
+				return
+			}
+		}
+
+		if policy == ReorderReject {
+			if !trySendSignal(slots) {
+				rejected := ErrorResult[Out](fmt.Errorf("%w: %w", ErrBusinessLogic, ErrReorderWindowFull))
+				doneCh <- seqResult[Out]{res: rejected}
+
+				continue
+			}
+		} else if !sendContext(ctx, slots, struct{}{}) {
+			return
+		}
+
+		if !sendContext(ctx, taskCh, seqInput[In]{seq: seq, in: in}) {
+			return
+		}
+
+		seq++
+	}
+}
+
+// emitOrdered sends the results in the input order and frees their window slots.
+// When the workers are done, the rest of the pending results are flushed in order, skipping the missing ones.
+func emitOrdered[Out any](
+	ctx context.Context,
+	doneCh <-chan seqResult[Out],
+	resCh chan<- Result[Out],
+	slots <-chan struct{},
+) {
+	pending := make(map[uint64]Result[Out])
+
+	var next uint64
+
+	emit := func(res Result[Out]) {
+		sendContext(ctx, resCh, res)
+		<-slots
+	}
+
+	for done := range doneCh {
+		if !done.ordered {
+			sendContext(ctx, resCh, done.res)
+			continue
+		}
+
+		pending[done.seq] = done.res
+
+		for res, ok := pending[next]; ok; res, ok = pending[next] {
+			delete(pending, next)
+			emit(res)
+			next++
+		}
+	}
+
+	for len(pending) > 0 {
+		if res, ok := pending[next]; ok {
+			delete(pending, next)
+			emit(res)
+		}
+
+		next++
+	}
+}
+
+// trySendSignal tries to send the signal into the channel without blocking.
+func trySendSignal(ch chan<- struct{}) bool {
+	select {
+	case ch <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,264 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestRunOrderedWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	const inputs = 20
+
+	ctx := context.Background()
+
+	testCases := map[string]struct {
+		givenPoolSize   int
+		givenWindowSize int
+	}{
+		"single_worker": {
+			givenPoolSize:   1,
+			givenWindowSize: 1,
+		},
+		"window_equals_pool": {
+			givenPoolSize:   4,
+			givenWindowSize: 4,
+		},
+		"window_wider_than_pool": {
+			givenPoolSize:   4,
+			givenWindowSize: 10,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			inCh := make(chan string)
+
+			go func() {
+				defer close(inCh)
+
+				for i := 0; i < inputs; i++ {
+					inCh <- strconv.Itoa(i)
+				}
+			}()
+
+			// The earlier inputs take longer, so without the reordering the results come reversed.
+			call := func(ctx context.Context, in string) (int, error) {
+				i, err := strconv.Atoi(in)
+				if err != nil {
+					return 0, err
+				}
+
+				return stabCall(time.Duration(inputs-i)*time.Millisecond)(ctx, in)
+			}
+
+			resCh, err := merec.RunOrderedWorkerPool(ctx, inCh, call, tc.givenPoolSize, tc.givenWindowSize, merec.ReorderBlock)
+			require.NoError(t, err)
+
+			results := make([]merec.Result[int], 0, inputs)
+
+			for r := range resCh {
+				results = append(results, r)
+			}
+
+			expRes := make([]merec.Result[int], inputs)
+			for i := range expRes {
+				expRes[i] = merec.ValueResult(i)
+			}
+
+			require.Equal(t, expRes, results)
+		})
+	}
+}
+
+func TestRunOrderedWorkerPool_Window(t *testing.T) {
+	t.Parallel()
+
+	const (
+		inputs     = 10
+		windowSize = 3
+	)
+
+	testCases := map[string]struct {
+		givenPolicy merec.ReorderPolicy
+		expStarted  int32
+		expRes      []merec.Result[int]
+	}{
+		"block": {
+			givenPolicy: merec.ReorderBlock,
+			expStarted:  inputs,
+			expRes:      expectedOrderedResults(inputs),
+		},
+		"reject": {
+			givenPolicy: merec.ReorderReject,
+			expStarted:  windowSize,
+			expRes: append(
+				repeatResult(merec.ErrorResult[int](merec.ErrReorderWindowFull), inputs-windowSize),
+				expectedOrderedResults(windowSize)...,
+			),
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			inCh := make(chan string, inputs)
+			for i := 0; i < inputs; i++ {
+				inCh <- strconv.Itoa(i)
+			}
+			close(inCh)
+
+			var started atomic.Int32
+
+			release := make(chan struct{})
+			call := func(ctx context.Context, in string) (int, error) {
+				started.Add(1)
+
+				if in == "0" {
+					<-release
+				}
+
+				return stabCall(0)(ctx, in)
+			}
+
+			resCh, err := merec.RunOrderedWorkerPool(context.Background(), inCh, call, windowSize, windowSize, tc.givenPolicy)
+			require.NoError(t, err)
+
+			// The first input holds the window, so no more inputs are started.
+			require.Eventually(t, func() bool { return started.Load() == windowSize }, time.Second, time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+			require.Equal(t, int32(windowSize), started.Load())
+
+			// The rejected results are emitted while the first input is still in progress.
+			rejected := inputs - int(tc.expStarted)
+			results := make([]merec.Result[int], 0, len(tc.expRes))
+
+			for len(results) < rejected {
+				results = append(results, <-resCh)
+			}
+
+			close(release)
+
+			for r := range resCh {
+				results = append(results, r)
+			}
+
+			require.Equal(t, tc.expStarted, started.Load())
+			require.Len(t, results, len(tc.expRes))
+
+			for i, r := range results {
+				require.ErrorIs(t, r.Err(), tc.expRes[i].Err())
+				require.Equal(t, tc.expRes[i].Value(), r.Value())
+			}
+		})
+	}
+}
+
+func TestRunOrderedWorkerPool_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	defer ctxCsl()
+
+	resCh, err := merec.RunOrderedWorkerPool(ctx, endlessCh(workLoad), stabCall(time.Hour), 2, 4, merec.ReorderBlock)
+	require.NoError(t, err)
+
+	ctxCsl()
+
+	var results []merec.Result[int]
+
+	for r := range resCh {
+		results = append(results, r)
+	}
+
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err(), merec.ErrCtxCancel)
+}
+
+func TestRunOrderedWorkerPool_ValidationFail(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		givenCtx  context.Context
+		givenIn   chan string
+		givenCall merec.Call[string, int]
+		givenSize int
+		expErr    error
+	}{
+		"nil_ctx": {
+			givenIn:   givenCh(0),
+			givenCall: stabCall(time.Second),
+			givenSize: 1,
+			expErr:    merec.ErrNilContext,
+		},
+		"nil_in_chan": {
+			givenCtx:  context.Background(),
+			givenCall: stabCall(time.Second),
+			givenSize: 1,
+			expErr:    merec.ErrNilInChan,
+		},
+		"nil_call_function": {
+			givenCtx:  context.Background(),
+			givenIn:   givenCh(0),
+			givenSize: 1,
+			expErr:    merec.ErrNilCallFunc,
+		},
+		"zero_pool_size": {
+			givenCtx:  context.Background(),
+			givenIn:   givenCh(0),
+			givenCall: stabCall(time.Second),
+			expErr:    merec.ErrInvalidPoolSize,
+		},
+		"negative_pool_size": {
+			givenCtx:  context.Background(),
+			givenIn:   givenCh(0),
+			givenCall: stabCall(time.Second),
+			givenSize: -1,
+			expErr:    merec.ErrInvalidPoolSize,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			resCh, err := merec.RunOrderedWorkerPool(tc.givenCtx, tc.givenIn, tc.givenCall, tc.givenSize, 1, merec.ReorderBlock)
+			require.ErrorIs(t, err, tc.expErr)
+			require.Nil(t, resCh)
+		})
+	}
+}
+
+func expectedOrderedResults(n int) []merec.Result[int] {
+	values := make([]merec.Result[int], n)
+	for i := range values {
+		values[i] = merec.ValueResult(i)
+	}
+
+	return values
+}
+
+func repeatResult(r merec.Result[int], n int) []merec.Result[int] {
+	values := make([]merec.Result[int], n)
+	for i := range values {
+		values[i] = r
+	}
+
+	return values
+}
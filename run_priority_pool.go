@@ -0,0 +1,173 @@
+package merec
+
+import (
+	"container/heap"
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// RunPriorityWorkerPool starts the pool of goroutine workers to consume from the input channel and execute
+// the call functions with inputs, like the RunWorkerPool. Unlike it, up to queueSize inputs are read ahead,
+// and the free worker always gets the pending input with the highest priority returned by the priorityOf.
+// The inputs with the same priority are dispatched in the input order.
+// Every aging period spent in the queue raises the input priority by one, so the low-priority inputs don't starve.
+// The non-positive aging disables it.
+func RunPriorityWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	priorityOf func(In) int,
+	aging time.Duration,
+	poolSize int,
+	queueSize int,
+	options ...CallOption[In, Out],
+) (<-chan Result[Out], error) {
+	return runPriorityWorkerPool(ctx, inCh, withOptions(call, options), priorityOf, aging, poolSize, queueSize)
+}
+
+func runPriorityWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	priorityOf func(In) int,
+	aging time.Duration,
+	poolSize int,
+	queueSize int,
+) (<-chan Result[Out], error) {
+	if err := validateRunFromChanInputs(ctx, inCh, call); err != nil {
+		return nil, err
+	}
+
+	if priorityOf == nil {
+		return nil, ErrNilPriorityFunc
+	}
+
+	if poolSize < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+
+	poolCtx, poolCtxCsl := context.WithCancel(ctx)
+
+	taskCh := make(chan In)
+	resChanPool := SpawnResChanPool[Result[Out]](poolSize, 0)
+
+	go dispatchByPriority(poolCtx, inCh, taskCh, priorityOf, aging, max(queueSize, 1))
+
+	var seq atomic.Uint64
+
+	worker := func(id int, resCh chan Result[Out]) {
+		defer close(resCh)
+
+		if consume(withWorker(poolCtx, &seq, id), taskCh, call, resCh, nil, nil) {
+			poolCtxCsl()
+		}
+	}
+
+	for i := 0; i < poolSize; i++ {
+		go worker(i, resChanPool[i])
+	}
+
+	return mergeChanPoolContext(ctx, resChanPool, poolCtxCsl), nil
+}
+
+// dispatchByPriority reads the inputs into the priority queue and passes the top one to the free worker.
+// The task channel is closed when the input channel is closed and the queue is drained, or the context is done.
+func dispatchByPriority[In any](
+	ctx context.Context,
+	inCh <-chan In,
+	taskCh chan<- In,
+	priorityOf func(In) int,
+	aging time.Duration,
+	queueSize int,
+) {
+	defer close(taskCh)
+
+	queue := &priorityQueue[In]{}
+	start := time.Now()
+
+	var seq uint64
+
+	for inCh != nil || queue.Len() > 0 {
+		var (
+			sendCh chan<- In
+			top    In
+			recvCh <-chan In
+		)
+
+		if queue.Len() > 0 {
+			sendCh, top = taskCh, queue.items[0].in
+		}
+
+		if queue.Len() < queueSize {
+			recvCh = inCh
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case sendCh <- top:
+			heap.Pop(queue)
+		case in, ok := <-recvCh:
+			if !ok {
+				inCh = nil
+				continue
+			}
+
+			// All the pending inputs age at the same pace, so the aging is applied as the penalty
+			// for the later arrival, and the order of the queue never changes.
+			rank := float64(priorityOf(in))
+			if aging > 0 {
+				rank -= float64(time.Since(start)) / float64(aging)
+			}
+
+			heap.Push(queue, priorityItem[In]{in: in, rank: rank, seq: seq})
+			seq++
+		}
+	}
+}
+
+type priorityItem[In any] struct {
+	in   In
+	rank float64
+	seq  uint64
+}
+
+// priorityQueue implements the heap.Interface, the item with the highest rank goes first.
+type priorityQueue[In any] struct {
+	items []priorityItem[In]
+}
+
+// Len implements the heap.Interface for the priorityQueue.
+func (pq *priorityQueue[In]) Len() int {
+	return len(pq.items)
+}
+
+// Less implements the heap.Interface for the priorityQueue.
+func (pq *priorityQueue[In]) Less(i, j int) bool {
+	if pq.items[i].rank != pq.items[j].rank {
+		return pq.items[i].rank > pq.items[j].rank
+	}
+
+	return pq.items[i].seq < pq.items[j].seq
+}
+
+// Swap implements the heap.Interface for the priorityQueue.
+func (pq *priorityQueue[In]) Swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+}
+
+// Push implements the heap.Interface for the priorityQueue.
+func (pq *priorityQueue[In]) Push(x any) {
+	pq.items = append(pq.items, x.(priorityItem[In]))
+}
+
+// Pop implements the heap.Interface for the priorityQueue.
+func (pq *priorityQueue[In]) Pop() any {
+	last := len(pq.items) - 1
+	item := pq.items[last]
+	pq.items[last] = priorityItem[In]{}
+	pq.items = pq.items[:last]
+
+	return item
+}
@@ -0,0 +1,109 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestRunPriorityWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	priorityOf := func(in string) int {
+		v, _ := strconv.Atoi(in)
+		return v
+	}
+
+	testCases := map[string]struct {
+		givenAging time.Duration
+		givenIns   []string
+		expValues  []int
+	}{
+		"highest_priority_first": {
+			givenIns:  []string{"3", "9", "1", "9", "5"},
+			expValues: []int{0, 9, 9, 5, 3, 1},
+		},
+		"aging_prevents_starvation": {
+			givenAging: time.Nanosecond,
+			givenIns:   []string{"3", "9", "1", "5"},
+			expValues:  []int{0, 3, 9, 1, 5},
+		},
+	}
+
+	for tcName, tc := range testCases {
+		tc := tc
+
+		t.Run(tcName, func(t *testing.T) {
+			t.Parallel()
+
+			started, release := make(chan struct{}), make(chan struct{})
+			call := func(ctx context.Context, in string) (int, error) {
+				if in == "0" {
+					close(started)
+					<-release
+				}
+
+				return stabCall(0)(ctx, in)
+			}
+
+			inCh := make(chan string)
+
+			resCh, err := merec.RunPriorityWorkerPool(ctx, inCh, call, priorityOf, tc.givenAging, 1, len(tc.givenIns))
+			require.NoError(t, err)
+
+			inCh <- "0"
+			<-started
+
+			for _, in := range tc.givenIns {
+				inCh <- in
+				time.Sleep(time.Millisecond)
+			}
+
+			close(inCh)
+			close(release)
+
+			values := make([]int, 0, len(tc.expValues))
+
+			for res := range resCh {
+				require.NoError(t, res.Err())
+
+				values = append(values, res.Value())
+			}
+
+			require.Equal(t, tc.expValues, values)
+		})
+	}
+}
+
+func TestRunPriorityWorkerPool_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := merec.RunPriorityWorkerPool(context.Background(), givenCh(0), stabCall(0), nil, 0, 1, 1)
+	require.ErrorIs(t, err, merec.ErrNilPriorityFunc)
+
+	for _, poolSize := range []int{0, -1} {
+		_, err = merec.RunPriorityWorkerPool(context.Background(), givenCh(0), stabCall(0), func(string) int { return 0 }, 0, poolSize, 1)
+		require.ErrorIs(t, err, merec.ErrInvalidPoolSize)
+	}
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	resCh, err := merec.RunPriorityWorkerPool(ctx, endlessCh(workLoad), stabCall(time.Hour), func(string) int { return 0 }, 0, 2, 1)
+	require.NoError(t, err)
+
+	ctxCsl()
+
+	results := make([]merec.Result[int], 0, 1)
+
+	for res := range resCh {
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err(), merec.ErrCtxCancel)
+}
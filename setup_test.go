@@ -3,9 +3,15 @@ package merec_test
 import (
 	"context"
 	"errors"
+	"runtime"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/DenisGoldiner/merec"
 )
 
@@ -57,3 +63,111 @@ func expectedResults() []merec.Result[int] {
 
 	return values
 }
+
+var errFlaky = errors.New("flaky call failed")
+
+// flakyCall fails the first failures executions and then behaves like the stabCall.
+func flakyCall(failures int32, duration time.Duration) (merec.Call[string, int], *atomic.Int32) {
+	var calls atomic.Int32
+
+	call := stabCall(duration)
+
+	return func(ctx context.Context, in string) (int, error) {
+		if calls.Add(1) <= failures {
+			return 0, errFlaky
+		}
+
+		return call(ctx, in)
+	}, &calls
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// fakeClock implements the merec.Clock, its time moves only with the Advance calls.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+
+	return ch
+}
+
+// Advance moves the time forward and fires all the due waiters.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.waiters[:0]
+
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+
+		w.ch <- c.now
+	}
+
+	c.waiters = pending
+}
+
+// Waiters returns the number of the pending After calls.
+func (c *fakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// requireNoGoroutineLeak checks that the number of goroutines returns to the baseline.
+// The tests using it must not run in parallel.
+func requireNoGoroutineLeak(t *testing.T, baseline int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	require.LessOrEqual(t, runtime.NumGoroutine(), baseline, "goroutines leaked")
+}
+
+// endlessCh returns the channel that is never closed, with some inputs buffered.
+func endlessCh(bufSize int) chan string {
+	ch := make(chan string, bufSize)
+	for i := 0; i < bufSize; i++ {
+		ch <- strconv.Itoa(i)
+	}
+
+	return ch
+}
@@ -0,0 +1,107 @@
+package merec
+
+import (
+	"context"
+	"sync"
+)
+
+type flight[Out any] struct {
+	done    chan struct{}
+	out     Out
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flightGroup keeps the executions in flight of the single wrapped call.
+type flightGroup[In, Out any, K comparable] struct {
+	next Call[In, Out]
+
+	mu      sync.Mutex
+	flights map[K]*flight[Out]
+}
+
+type singleflightOption[In, Out any, K comparable] struct {
+	keyOf func(In) K
+}
+
+// NewSingleflightOption is a constructor for the singleflightOption.
+// The concurrent calls with the same key returned by the keyOf are merged into a single execution,
+// and all of them get its result. The value is shared as is, so the waiters must not modify it.
+// The context of every waiter limits only its own waiting. The execution is canceled
+// when all the waiters are gone, otherwise it continues even if the context of the first waiter is done.
+// Only the calls of the same runner are merged, even if the option is passed to several of them.
+func NewSingleflightOption[In, Out any, K comparable](keyOf func(In) K) CallOption[In, Out] {
+	return singleflightOption[In, Out, K]{keyOf: keyOf}
+}
+
+// WithOption implements the CallOption interface for the singleflightOption.
+func (so singleflightOption[In, Out, K]) WithOption(next Call[In, Out]) Call[In, Out] {
+	group := &flightGroup[In, Out, K]{next: next, flights: make(map[K]*flight[Out])}
+
+	return func(ctx context.Context, in In) (Out, error) {
+		key := so.keyOf(in)
+
+		group.mu.Lock()
+
+		f, ok := group.flights[key]
+		if !ok {
+			f = group.start(ctx, key, in)
+		}
+
+		f.waiters++
+
+		group.mu.Unlock()
+
+		select {
+		case <-f.done:
+			return f.out, f.err
+		case <-ctx.Done():
+			group.leave(key, f)
+			return *new(Out), CheckContext(ctx)
+		}
+	}
+}
+
+// start executes the call for the key in a separate goroutine. It must be called with the mutex locked.
+func (g *flightGroup[In, Out, K]) start(ctx context.Context, key K, in In) *flight[Out] {
+	// The execution outlives the first waiter, so it doesn't fill the metadata of the waiter in.
+	execCtx, execCtxCsl := context.WithCancel(withoutMetaSlot(context.WithoutCancel(ctx)))
+
+	f := &flight[Out]{done: make(chan struct{}), cancel: execCtxCsl}
+	g.flights[key] = f
+
+	go func() {
+		defer execCtxCsl()
+
+		out, err := g.next(execCtx, in)
+
+		g.mu.Lock()
+		if g.flights[key] == f {
+			delete(g.flights, key)
+		}
+		g.mu.Unlock()
+
+		f.out, f.err = out, err
+		close(f.done)
+	}()
+
+	return f
+}
+
+// leave unregisters the waiter, and cancels the execution if it was the last one.
+func (g *flightGroup[In, Out, K]) leave(key K, f *flight[Out]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+
+	f.cancel()
+}
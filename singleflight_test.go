@@ -0,0 +1,147 @@
+package merec_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestSingleflightOption(t *testing.T) {
+	t.Parallel()
+
+	const waiters = 10
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	call := func(ctx context.Context, in string) (int, error) {
+		calls.Add(1)
+
+		if in == "1" {
+			<-release
+		}
+
+		return stabCall(0)(ctx, in)
+	}
+
+	option := merec.NewSingleflightOption[string, int](func(in string) string { return in })
+	merged := option.WithOption(call)
+
+	var wg sync.WaitGroup
+
+	results := make([]merec.Result[int], waiters)
+
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			out, err := merged(context.Background(), "1")
+			if assert.NoError(t, err) {
+				results[i] = merec.ValueResult(out)
+			}
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	out, err := merged(context.Background(), "qwerty")
+	require.Error(t, err)
+	require.Zero(t, out)
+	require.Equal(t, int32(2), calls.Load())
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, res := range results {
+		require.Equal(t, merec.ValueResult(1), res)
+	}
+
+	require.Equal(t, int32(2), calls.Load())
+
+	_, err = merged(context.Background(), "1")
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestSingleflightOption_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	var canceled atomic.Bool
+
+	call := func(ctx context.Context, in string) (int, error) {
+		out, err := stabCall(time.Hour)(ctx, in)
+		canceled.Store(err != nil)
+
+		return out, err
+	}
+
+	merged := merec.NewSingleflightOption[string, int](func(in string) string { return in }).WithOption(call)
+
+	firstCtx, firstCtxCsl := context.WithCancel(context.Background())
+	secondCtx, secondCtxCsl := context.WithTimeout(context.Background(), 30*time.Millisecond)
+
+	defer secondCtxCsl()
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		_, err := merged(firstCtx, "1")
+		assert.ErrorIs(t, err, merec.ErrCtxCancel)
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		_, err := merged(secondCtx, "1")
+		assert.ErrorIs(t, err, merec.ErrCtxDeadline)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	firstCtxCsl()
+	time.Sleep(10 * time.Millisecond)
+	require.False(t, canceled.Load())
+
+	wg.Wait()
+	require.Eventually(t, canceled.Load, time.Second, time.Millisecond)
+}
+
+func TestSingleflightOption_NotSharedByCalls(t *testing.T) {
+	t.Parallel()
+
+	option := merec.NewSingleflightOption[string, int](func(string) string { return "key" })
+
+	started, release := make(chan struct{}), make(chan struct{})
+	first := option.WithOption(func(context.Context, string) (int, error) {
+		close(started)
+		<-release
+
+		return 1, nil
+	})
+	second := option.WithOption(func(context.Context, string) (int, error) { return 2, nil })
+
+	go func() {
+		_, _ = first(context.Background(), "1")
+	}()
+
+	<-started
+
+	out, err := second(context.Background(), "1")
+	require.NoError(t, err)
+	require.Equal(t, 2, out)
+
+	close(release)
+}
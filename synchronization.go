@@ -3,6 +3,7 @@ package merec
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 // CheckContext checks if the context is done without blocking the execution.
@@ -19,3 +20,62 @@ func CheckContext(ctx context.Context) error {
 		return nil
 	}
 }
+
+// sleepContext pauses the execution for the delay or until the context is done.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return CheckContext(ctx)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return CheckContext(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sendContext sends the value into the channel unless the context is done.
+// Returns false if the value was dropped because of the context.
+func sendContext[T any](ctx context.Context, ch chan<- T, v T) bool {
+	if CheckContext(ctx) != nil {
+		return false
+	}
+
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ctxErrWait limits how long the final context error waits for the consumer to make room in the full output.
+const ctxErrWait = 100 * time.Millisecond
+
+// newResCh creates the output channel of the runner. The extra slot lets the final context error in
+// without waiting if the consumer has read all the values.
+func newResCh[Out any](bufSize int) chan Result[Out] {
+	return make(chan Result[Out], bufSize+1)
+}
+
+// sendCtxErr sends the ErrCtxCancel or ErrCtxDeadline result if the context is done. It must be called
+// after the last value is sent into the channel. If the output is full, it waits for the consumer to read
+// for the ctxErrWait at most, so it never blocks on the abandoned output, and the unread values are kept.
+func sendCtxErr[Out any](ctx context.Context, ch chan<- Result[Out]) {
+	err := CheckContext(ctx)
+	if err == nil {
+		return
+	}
+
+	timer := time.NewTimer(ctxErrWait)
+	defer timer.Stop()
+
+	select {
+	case ch <- ErrorResult[Out](err):
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,156 @@
+package merec
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// WorkerPool is the pool of goroutine workers consuming from the input channel, like the RunWorkerPool.
+// Unlike it, the number of workers can be changed with the Resize while the pool is running.
+type WorkerPool[In, Out any] struct {
+	ctx        context.Context
+	poolCtx    context.Context
+	poolCtxCsl context.CancelFunc
+	inCh       <-chan In
+	call       Call[In, Out]
+	resCh      chan Result[Out]
+	// done is closed when all the workers are finished.
+	done chan struct{}
+
+	seq atomic.Uint64
+
+	mu       sync.Mutex
+	quits    []chan struct{}
+	running  int
+	finished bool
+	// spawned is the number of the workers ever started, it is used as the ID of the next one.
+	spawned int
+}
+
+// NewWorkerPool starts the pool of poolSize goroutine workers to consume from the input channel and execute
+// the call functions with inputs. The Result values are available in the channel returned by the Results.
+// Once the context is done, it stops consuming, drops the results of the calls in flight,
+// and finishes with the ErrCtxCancel or ErrCtxDeadline result, waiting a little for the consumer if the output is full.
+func NewWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	poolSize int,
+	bufSize int,
+	options ...CallOption[In, Out],
+) (*WorkerPool[In, Out], error) {
+	return newWorkerPool(ctx, inCh, withOptions(call, options), poolSize, bufSize)
+}
+
+func newWorkerPool[In, Out any](
+	ctx context.Context,
+	inCh <-chan In,
+	call Call[In, Out],
+	poolSize int,
+	bufSize int,
+) (*WorkerPool[In, Out], error) {
+	if err := validateRunFromChanInputs(ctx, inCh, call); err != nil {
+		return nil, err
+	}
+
+	if poolSize < 1 {
+		return nil, ErrInvalidPoolSize
+	}
+
+	poolCtx, poolCtxCsl := context.WithCancel(ctx)
+
+	p := &WorkerPool[In, Out]{
+		ctx:        ctx,
+		poolCtx:    poolCtx,
+		poolCtxCsl: poolCtxCsl,
+		inCh:       inCh,
+		call:       call,
+		resCh:      newResCh[Out](bufSize),
+		done:       make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	p.spawn(poolSize)
+	p.mu.Unlock()
+
+	return p, nil
+}
+
+// Results returns the channel to be listened to, to get the Result values.
+// It is closed when all the workers are finished.
+func (p *WorkerPool[In, Out]) Results() <-chan Result[Out] {
+	return p.resCh
+}
+
+// Size returns the current number of workers.
+func (p *WorkerPool[In, Out]) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.quits)
+}
+
+// Resize changes the number of workers. The new workers start consuming immediately.
+// The retired workers complete the calls in flight and emit their results before they stop.
+// Returns the ErrWorkerPoolFinished if all the workers are already finished.
+func (p *WorkerPool[In, Out]) Resize(poolSize int) error {
+	if poolSize < 1 {
+		return ErrInvalidPoolSize
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return ErrWorkerPoolFinished
+	}
+
+	if poolSize > len(p.quits) {
+		p.spawn(poolSize - len(p.quits))
+		return nil
+	}
+
+	for _, quit := range p.quits[poolSize:] {
+		close(quit)
+	}
+
+	p.quits = p.quits[:poolSize]
+
+	return nil
+}
+
+// spawn starts the workers. It must be called with the mutex locked.
+func (p *WorkerPool[In, Out]) spawn(workers int) {
+	p.running += workers
+
+	for i := 0; i < workers; i++ {
+		quit := make(chan struct{})
+		p.quits = append(p.quits, quit)
+
+		go p.work(p.spawned, quit)
+		p.spawned++
+	}
+}
+
+func (p *WorkerPool[In, Out]) work(id int, quit <-chan struct{}) {
+	if consume(withWorker(p.poolCtx, &p.seq, id), p.inCh, p.call, p.resCh, quit, nil) {
+		p.poolCtxCsl()
+	}
+
+	p.mu.Lock()
+	p.running--
+	finished := p.running == 0
+	p.finished = finished
+	p.mu.Unlock()
+
+	if !finished {
+		return
+	}
+
+	p.poolCtxCsl()
+	close(p.done)
+
+	sendCtxErr(p.ctx, p.resCh)
+	close(p.resCh)
+}
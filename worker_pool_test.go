@@ -0,0 +1,111 @@
+package merec_test
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DenisGoldiner/merec"
+)
+
+func TestWorkerPool_Resize(t *testing.T) {
+	t.Parallel()
+
+	const inputs = 40
+
+	var running, maxRunning atomic.Int32
+
+	call := func(ctx context.Context, in string) (int, error) {
+		cur := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			prev := maxRunning.Load()
+			if cur <= prev || maxRunning.CompareAndSwap(prev, cur) {
+				break
+			}
+		}
+
+		return stabCall(5*time.Millisecond)(ctx, in)
+	}
+
+	inCh := make(chan string)
+
+	pool, err := merec.NewWorkerPool[string, int](context.Background(), inCh, call, 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, pool.Size())
+
+	go func() {
+		defer close(inCh)
+
+		for i := 0; i < inputs; i++ {
+			switch i {
+			case 10:
+				assert.NoError(t, pool.Resize(4))
+			case 30:
+				assert.NoError(t, pool.Resize(1))
+			}
+
+			inCh <- strconv.Itoa(i)
+		}
+	}()
+
+	expected := make([]merec.Result[int], inputs)
+	for i := range expected {
+		expected[i] = merec.ValueResult(i)
+	}
+
+	results := make([]merec.Result[int], 0, inputs)
+
+	for res := range pool.Results() {
+		results = append(results, res)
+	}
+
+	require.ElementsMatch(t, expected, results)
+	require.Greater(t, maxRunning.Load(), int32(1))
+	require.Equal(t, 1, pool.Size())
+	require.ErrorIs(t, pool.Resize(2), merec.ErrWorkerPoolFinished)
+}
+
+func TestWorkerPool_Errors(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+	defer ctxCsl()
+
+	_, err := merec.NewWorkerPool(ctx, givenCh(0), stabCall(0), 0, 0)
+	require.ErrorIs(t, err, merec.ErrInvalidPoolSize)
+
+	_, err = merec.NewWorkerPool(ctx, nil, stabCall(0), 1, 0)
+	require.ErrorIs(t, err, merec.ErrNilInChan)
+
+	pool, err := merec.NewWorkerPool(ctx, endlessCh(0), stabCall(0), 1, 0)
+	require.NoError(t, err)
+	require.ErrorIs(t, pool.Resize(0), merec.ErrInvalidPoolSize)
+}
+
+func TestWorkerPool_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, ctxCsl := context.WithCancel(context.Background())
+
+	pool, err := merec.NewWorkerPool(ctx, endlessCh(workLoad), stabCall(time.Hour), 2, 0)
+	require.NoError(t, err)
+	require.NoError(t, pool.Resize(3))
+
+	ctxCsl()
+
+	results := make([]merec.Result[int], 0, 1)
+
+	for res := range pool.Results() {
+		results = append(results, res)
+	}
+
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err(), merec.ErrCtxCancel)
+}